@@ -0,0 +1,167 @@
+package form3apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Classification is the classification of an account (see AccountAttributes.AccountClassification).
+// Values unknown to this package are decoded as they are (see IsKnown), but cannot be encoded.
+type Classification string
+
+// Account classifications.
+const (
+	ClassificationPersonal Classification = "Personal"
+	ClassificationBusiness Classification = "Business"
+)
+
+var knownClassifications = []Classification{ClassificationPersonal, ClassificationBusiness}
+
+// String implements fmt.Stringer.
+func (c Classification) String() string {
+	return string(c)
+}
+
+// IsKnown reports whether the classification is one of the Classification... constants.
+func (c Classification) IsKnown() bool {
+	return isKnownValue(c, knownClassifications)
+}
+
+// MarshalJSON implements json.Marshaler. Returns an error wrapping ErrUnknownValue for unknown classifications.
+func (c Classification) MarshalJSON() ([]byte, error) {
+	return marshalKnownValue(c, knownClassifications, "account classification")
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown classifications are kept (see IsKnown).
+func (c *Classification) UnmarshalJSON(data []byte) error {
+	return unmarshalValue(data, c, "account classification")
+}
+
+// AccountStatus is the status of an account (see AccountAttributes.Status).
+// Values unknown to this package are decoded as they are (see IsKnown), but cannot be encoded.
+type AccountStatus string
+
+// Account statuses.
+const (
+	AccountStatusPending   AccountStatus = "pending"
+	AccountStatusConfirmed AccountStatus = "confirmed"
+	AccountStatusFailed    AccountStatus = "failed"
+	AccountStatusClosed    AccountStatus = "closed"
+)
+
+var knownAccountStatuses = []AccountStatus{
+	AccountStatusPending,
+	AccountStatusConfirmed,
+	AccountStatusFailed,
+	AccountStatusClosed,
+}
+
+// String implements fmt.Stringer.
+func (s AccountStatus) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether the status is one of the AccountStatus... constants.
+func (s AccountStatus) IsKnown() bool {
+	return isKnownValue(s, knownAccountStatuses)
+}
+
+// MarshalJSON implements json.Marshaler. Returns an error wrapping ErrUnknownValue for unknown statuses.
+func (s AccountStatus) MarshalJSON() ([]byte, error) {
+	return marshalKnownValue(s, knownAccountStatuses, "account status")
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown statuses are kept (see IsKnown).
+func (s *AccountStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalValue(data, s, "account status")
+}
+
+// BankIDCode identifies the national scheme of AccountAttributes.BankID (e.g. GBDSC for UK sort codes).
+// Values unknown to this package are decoded as they are (see IsKnown), but cannot be encoded.
+type BankIDCode string
+
+// Bank id codes of the supported countries.
+const (
+	BankIDCodeAustralia   BankIDCode = "AUBSB"
+	BankIDCodeBelgium     BankIDCode = "BE"
+	BankIDCodeCanada      BankIDCode = "CACPA"
+	BankIDCodeFrance      BankIDCode = "FR"
+	BankIDCodeGermany     BankIDCode = "DEBLZ"
+	BankIDCodeGreece      BankIDCode = "GRBIC"
+	BankIDCodeHongKong    BankIDCode = "HKNCC"
+	BankIDCodeItaly       BankIDCode = "ITNCC"
+	BankIDCodeLuxembourg  BankIDCode = "LULUX"
+	BankIDCodePoland      BankIDCode = "PLKNR"
+	BankIDCodePortugal    BankIDCode = "PTNCC"
+	BankIDCodeSpain       BankIDCode = "ESNCC"
+	BankIDCodeSwitzerland BankIDCode = "CHBCC"
+	BankIDCodeUK          BankIDCode = "GBDSC"
+	BankIDCodeUSA         BankIDCode = "USABA"
+)
+
+var knownBankIDCodes = []BankIDCode{
+	BankIDCodeAustralia,
+	BankIDCodeBelgium,
+	BankIDCodeCanada,
+	BankIDCodeFrance,
+	BankIDCodeGermany,
+	BankIDCodeGreece,
+	BankIDCodeHongKong,
+	BankIDCodeItaly,
+	BankIDCodeLuxembourg,
+	BankIDCodePoland,
+	BankIDCodePortugal,
+	BankIDCodeSpain,
+	BankIDCodeSwitzerland,
+	BankIDCodeUK,
+	BankIDCodeUSA,
+}
+
+// String implements fmt.Stringer.
+func (c BankIDCode) String() string {
+	return string(c)
+}
+
+// IsKnown reports whether the code is one of the BankIDCode... constants.
+func (c BankIDCode) IsKnown() bool {
+	return isKnownValue(c, knownBankIDCodes)
+}
+
+// MarshalJSON implements json.Marshaler. Returns an error wrapping ErrUnknownValue for unknown codes.
+func (c BankIDCode) MarshalJSON() ([]byte, error) {
+	return marshalKnownValue(c, knownBankIDCodes, "bank id code")
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown codes are kept (see IsKnown).
+func (c *BankIDCode) UnmarshalJSON(data []byte) error {
+	return unmarshalValue(data, c, "bank id code")
+}
+
+func isKnownValue[T ~string](value T, known []T) bool {
+	for _, knownValue := range known {
+		if value == knownValue {
+			return true
+		}
+	}
+
+	return false
+}
+
+func marshalKnownValue[T ~string](value T, known []T, kind string) ([]byte, error) {
+	if !isKnownValue(value, known) {
+		return nil, UnknownValueError(fmt.Sprintf(`"%s" is not a known %s`, value, kind))
+	}
+
+	return json.Marshal(string(value)) //nolint:wrapcheck // marshalling a string does not fail
+}
+
+func unmarshalValue[T ~string](data []byte, value *T, kind string) error {
+	var decoded string
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return WrapError(err, "decoding "+kind)
+	}
+
+	*value = T(decoded)
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package form3apiclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/form3apiclient"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+// enum is implemented by the account enums.
+type enum interface {
+	fmt.Stringer
+	IsKnown() bool
+}
+
+var _ = Describe("account enums", func() {
+	DescribeTable("encodes known value",
+		func(value enum, expectedJSON string) {
+			encoded, err := json.Marshal(value)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(encoded).To(MatchJSON(expectedJSON))
+			Expect(value.IsKnown()).To(BeTrue())
+			Expect(value.String()).To(Equal(expectedJSON[1 : len(expectedJSON)-1]))
+		},
+		Entry("classification", form3apiclient.ClassificationBusiness, `"Business"`),
+		Entry("account status", form3apiclient.AccountStatusPending, `"pending"`),
+		Entry("bank id code", form3apiclient.BankIDCodeUK, `"GBDSC"`),
+	)
+
+	DescribeTable("refuses to encode unknown value",
+		func(value enum) {
+			_, err := json.Marshal(value)
+
+			Expect(err).To(MatchError(form3apiclient.ErrUnknownValue))
+			Expect(value.IsKnown()).To(BeFalse())
+		},
+		Entry("classification", form3apiclient.Classification("personal")),
+		Entry("account status", form3apiclient.AccountStatus("active")),
+		Entry("bank id code", form3apiclient.BankIDCode("GBDCS")),
+		Entry("empty value", form3apiclient.AccountStatus("")),
+	)
+
+	It("decodes unknown values as they are", func() {
+		var attributes form3apiclient.AccountAttributes
+
+		err := json.Unmarshal(
+			[]byte(`{"account_classification":"Charity","status":"frozen","bank_id_code":"SEBGC"}`), &attributes)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attributes.AccountClassification).To(Equal(form3apiclient.Classification("Charity")))
+		Expect(attributes.AccountClassification.IsKnown()).To(BeFalse())
+		Expect(attributes.Status).To(Equal(form3apiclient.AccountStatus("frozen")))
+		Expect(attributes.Status.IsKnown()).To(BeFalse())
+		Expect(attributes.BankIDCode).To(Equal(form3apiclient.BankIDCode("SEBGC")))
+		Expect(attributes.BankIDCode.IsKnown()).To(BeFalse())
+	})
+
+	It("fails to decode non-string value", func() {
+		var attributes form3apiclient.AccountAttributes
+
+		err := json.Unmarshal([]byte(`{"status":1}`), &attributes)
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("does not send account with unknown value", func() {
+		server := ghttp.NewServer()
+		defer server.Close()
+
+		account := someValidAccountData(someValidUUID)
+		account.Attributes.BankIDCode = "GBDCS"
+
+		_, err := form3apiclient.MustNew(server.URL()).Accounts().Create(context.Background(), account)
+
+		Expect(err).To(MatchError(form3apiclient.ErrUnknownValue))
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("does not create account with unknown value if accounts are validated", func() {
+		server := ghttp.NewServer()
+		defer server.Close()
+
+		account := someValidAccountData(someValidUUID)
+		account.Attributes.AccountClassification = "personal"
+
+		_, err := form3apiclient.MustNew(server.URL(), form3apiclient.WithAccountValidation()).
+			Accounts().
+			Create(context.Background(), account)
+
+		Expect(err).To(MatchError(form3apiclient.ErrInvalidAccount))
+		Expect(err.Error()).To(ContainSubstring(`"personal" is not a known account classification`))
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+	})
+})
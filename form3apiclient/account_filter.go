@@ -0,0 +1,125 @@
+package form3apiclient
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	filterBankID        = "bank_id"
+	filterAccountNumber = "account_number"
+	filterIban          = "iban"
+	filterCountry       = "country"
+	filterCustomerID    = "customer_id"
+)
+
+var (
+	countryCodeRegexp = regexp.MustCompile(`^[A-Z]{2}$`)
+	ibanRegexp        = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+)
+
+// AccountFilter is a builder of server-side filters for account listing
+// (see ListOptions). Each filter accepts multiple values, in which case
+// accounts matching any of the values are listed. Different filters are combined,
+// i.e. listed accounts match all of them.
+//
+// Invalid filter values are reported by Validate and by the iterator returned from Accounts.List.
+//
+//	filter := form3apiclient.NewAccountFilter().
+//		Country("GB", "FR").
+//		BankID("400300")
+type AccountFilter struct {
+	values map[string][]string
+	err    error
+}
+
+// NewAccountFilter constructs an empty AccountFilter (matching all accounts).
+func NewAccountFilter() *AccountFilter {
+	return &AccountFilter{values: make(map[string][]string)}
+}
+
+// BankID filters accounts by bank ids (e.g. "400300").
+func (f *AccountFilter) BankID(bankIDs ...string) *AccountFilter {
+	return f.add(filterBankID, bankIDs, nil)
+}
+
+// AccountNumber filters accounts by account numbers.
+func (f *AccountFilter) AccountNumber(accountNumbers ...string) *AccountFilter {
+	return f.add(filterAccountNumber, accountNumbers, nil)
+}
+
+// Iban filters accounts by IBANs. Spaces are removed from the IBANs and letters are capitalized
+// (e.g. "gb11 nwbk 4003 0041 4265 26" is the same as "GB11NWBK40030041426526").
+func (f *AccountFilter) Iban(ibans ...string) *AccountFilter {
+	normalized := make([]string, len(ibans))
+	for i, iban := range ibans {
+		normalized[i] = strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+	}
+
+	return f.add(filterIban, normalized, ibanRegexp)
+}
+
+// Country filters accounts by ISO 3166-1 alpha-2 country codes (e.g. "GB").
+func (f *AccountFilter) Country(countryCodes ...string) *AccountFilter {
+	return f.add(filterCountry, countryCodes, countryCodeRegexp)
+}
+
+// CustomerID filters accounts by customer ids.
+func (f *AccountFilter) CustomerID(customerIDs ...string) *AccountFilter {
+	return f.add(filterCustomerID, customerIDs, nil)
+}
+
+// Validate returns the first problem found in the filter values (nil if all values are valid).
+func (f *AccountFilter) Validate() error {
+	return f.err
+}
+
+func (f *AccountFilter) add(name string, values []string, format *regexp.Regexp) *AccountFilter {
+	for _, value := range values {
+		if f.err != nil {
+			break
+		}
+
+		f.err = validateFilterValue(name, value, format)
+	}
+
+	f.values[name] = append(f.values[name], values...)
+
+	return f
+}
+
+func validateFilterValue(name string, value string, format *regexp.Regexp) error {
+	switch {
+	case value == "":
+		return InvalidFilterError(fmt.Sprintf(`empty value of "%s" filter`, name))
+	case strings.Contains(value, ","):
+		return InvalidFilterError(fmt.Sprintf(`value "%s" of "%s" filter contains a comma`, value, name))
+	case format != nil && !format.MatchString(value):
+		return InvalidFilterError(fmt.Sprintf(`value "%s" of "%s" filter has invalid format`, value, name))
+	default:
+		return nil
+	}
+}
+
+// queryParams encodes the filter as query params (multiple values are comma-separated).
+func (f *AccountFilter) queryParams() (map[string]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	queryParams := make(map[string]string, len(f.values))
+
+	for name, values := range f.values {
+		if len(values) == 0 {
+			continue
+		}
+
+		sorted := append([]string(nil), values...)
+		sort.Strings(sorted)
+		queryParams[fmt.Sprintf("filter[%s]", name)] = strings.Join(sorted, ",")
+	}
+
+	return queryParams, nil
+}
@@ -0,0 +1,86 @@
+package form3apiclient
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AccountFilter", func() {
+	It("encodes no query params when empty", func() {
+		queryParams, err := NewAccountFilter().queryParams()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(queryParams).To(BeEmpty())
+	})
+
+	It("encodes all filters as query params", func() {
+		queryParams, err := NewAccountFilter().
+			BankID("400300").
+			AccountNumber("41426819").
+			Iban("GB11NWBK40030041426819").
+			Country("GB").
+			CustomerID("some-customer").
+			queryParams()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(queryParams).To(Equal(map[string]string{
+			"filter[bank_id]":        "400300",
+			"filter[account_number]": "41426819",
+			"filter[iban]":           "GB11NWBK40030041426819",
+			"filter[country]":        "GB",
+			"filter[customer_id]":    "some-customer",
+		}))
+	})
+
+	It("encodes multiple values as a comma-separated list", func() {
+		queryParams, err := NewAccountFilter().
+			Country("GB", "FR").
+			Country("DE").
+			queryParams()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(queryParams).To(Equal(map[string]string{"filter[country]": "DE,FR,GB"}))
+	})
+
+	It("normalizes IBANs", func() {
+		queryParams, err := NewAccountFilter().Iban("gb11 nwbk 4003 0041 4268 19").queryParams()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(queryParams).To(Equal(map[string]string{"filter[iban]": "GB11NWBK40030041426819"}))
+	})
+
+	DescribeTable("reports invalid values",
+		func(filter *AccountFilter, expectedError string) {
+			queryParams, err := filter.queryParams()
+
+			Expect(filter.Validate()).To(MatchError(InvalidFilterError(expectedError)))
+			Expect(err).To(MatchError(InvalidFilterError(expectedError)))
+			Expect(queryParams).To(BeNil())
+		},
+		Entry("empty value", NewAccountFilter().BankID(""), `empty value of "bank_id" filter`),
+		Entry(
+			"value with comma",
+			NewAccountFilter().CustomerID("a,b"),
+			`value "a,b" of "customer_id" filter contains a comma`),
+		Entry(
+			"lowercase country code",
+			NewAccountFilter().Country("gb"),
+			`value "gb" of "country" filter has invalid format`),
+		Entry(
+			"too long country code",
+			NewAccountFilter().Country("GBR"),
+			`value "GBR" of "country" filter has invalid format`),
+		Entry(
+			"too short IBAN",
+			NewAccountFilter().Iban("GB11NWBK"),
+			`value "GB11NWBK" of "iban" filter has invalid format`),
+		Entry(
+			"IBAN with invalid characters",
+			NewAccountFilter().Iban("GB11-NWBK-4003-0041-4268-19"),
+			`value "GB11-NWBK-4003-0041-4268-19" of "iban" filter has invalid format`),
+		Entry(
+			"first of many invalid values",
+			NewAccountFilter().Country("GB", "X").BankID(""),
+			`value "X" of "country" filter has invalid format`),
+	)
+})
@@ -0,0 +1,76 @@
+package form3apiclient
+
+import (
+	"errors"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/form3apiclient/iban"
+)
+
+// WithIban returns a copy of the account with its IBAN filled in or cross-checked, e.g. before Accounts.Create.
+//
+// If the IBAN is not set, it is built from the country, the bank id, the account number and the BIC
+// (see iban.Build). Accounts of countries which do not use IBANs are returned as they are.
+//
+// If the IBAN is set, it is validated (see iban.Parse) and converted to the electronic format.
+// Returns an error wrapping ErrIbanMismatch if it does not match the country of the account
+// or the IBAN built from the other attributes (if the IBAN can be built for the country).
+func (a AccountData) WithIban() (AccountData, error) {
+	attributes := &a.Attributes
+
+	if attributes.Iban == "" {
+		if !iban.IsSupported(attributes.Country) {
+			return a, nil
+		}
+
+		built, err := iban.Build(ibanAccount(*attributes))
+		if err != nil {
+			return a, WrapError(err, "building iban")
+		}
+
+		attributes.Iban = built.String()
+
+		return a, nil
+	}
+
+	parsed, err := iban.Parse(attributes.Iban)
+	if err != nil {
+		return a, WrapError(err, "parsing iban")
+	}
+
+	if mismatch := ibanMismatch(parsed, *attributes); mismatch != "" {
+		return a, IbanMismatchError("iban " + mismatch)
+	}
+
+	attributes.Iban = parsed.String()
+
+	return a, nil
+}
+
+// ibanMismatch describes how the IBAN does not match the other attributes of the account
+// (e.g. "does not match country"). Returns an empty string if the IBAN matches.
+func ibanMismatch(parsed iban.IBAN, attributes AccountAttributes) string {
+	if parsed.CountryCode() != attributes.Country {
+		return "does not match country"
+	}
+
+	built, err := iban.Build(ibanAccount(attributes))
+
+	switch {
+	case errors.Is(err, iban.ErrCannotBuild):
+		// nothing to compare with
+		return ""
+	case built != parsed:
+		return "does not match bank id and account number"
+	default:
+		return ""
+	}
+}
+
+func ibanAccount(attributes AccountAttributes) iban.Account {
+	return iban.Account{
+		Country:       attributes.Country,
+		BankID:        attributes.BankID,
+		AccountNumber: attributes.AccountNumber,
+		Bic:           attributes.Bic.String(),
+	}
+}
@@ -0,0 +1,59 @@
+package form3apiclient_test
+
+import (
+	"github.com/jannis-baratheon/form3-take-home-exercise/form3apiclient"
+	"github.com/jannis-baratheon/form3-take-home-exercise/form3apiclient/iban"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func withIban(account form3apiclient.AccountData, value string) form3apiclient.AccountData {
+	account.Attributes.Iban = value
+
+	return account
+}
+
+var _ = Describe("AccountData.WithIban", func() {
+	DescribeTable("fills in or normalises IBAN",
+		func(account form3apiclient.AccountData, expectedIban string) {
+			completed, err := account.WithIban()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(completed).To(Equal(withIban(account, expectedIban)))
+		},
+		Entry("missing GB IBAN", validGBAccount(), "GB16NWBK40030041426819"),
+		Entry("matching IBAN in print format",
+			withIban(validGBAccount(), "gb16 nwbk 4003 0041 4268 19"), "GB16NWBK40030041426819"),
+		Entry("country without IBANs",
+			localAccount("US", "123456789", form3apiclient.BankIDCodeUSA, "NWBKUS22", "123456"), ""),
+		Entry("valid IBAN of country without IBAN building",
+			withIban(localAccount("FR", "2004101005", form3apiclient.BankIDCodeFrance, "", "0500013M026"),
+				"FR1420041010050500013M02606"),
+			"FR1420041010050500013M02606"),
+	)
+
+	DescribeTable("fails",
+		func(account form3apiclient.AccountData, expectedErr error) {
+			completed, err := account.WithIban()
+
+			Expect(err).To(MatchError(expectedErr))
+			Expect(completed).To(Equal(account))
+		},
+		Entry("for IBAN of other country",
+			withIban(validGBAccount(), "DE89370400440532013000"), form3apiclient.ErrIbanMismatch),
+		Entry("for IBAN of other account",
+			withIban(validGBAccount(), "GB82WEST12345698765432"), form3apiclient.ErrIbanMismatch),
+		Entry("for invalid IBAN",
+			withIban(validGBAccount(), "GB17NWBK40030041426819"), iban.ErrInvalidIBAN),
+		Entry("for missing IBAN which cannot be built",
+			localAccount("FR", "2004101005", form3apiclient.BankIDCodeFrance, "", "0500013M026"), iban.ErrCannotBuild),
+	)
+
+	It("makes validation report IBAN mismatch", func() {
+		errs := form3apiclient.ValidateAccount(withIban(validGBAccount(), "GB82WEST12345698765432"))
+
+		Expect(errs).To(Equal([]form3apiclient.ValidationError{
+			{Field: "attributes.iban", Message: "does not match bank id and account number"},
+		}))
+	})
+})
@@ -0,0 +1,59 @@
+package form3apiclient
+
+import (
+	"fmt"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
+)
+
+// ListOptions represents options of an account listing.
+type ListOptions struct {
+	// PageNumber is the number of the first page to be fetched (pages are numbered from 0).
+	PageNumber int
+	// PageSize is the maximal number of accounts fetched in a single request
+	// (the server default is used if PageSize is 0).
+	PageSize int
+	// Filter is an optional server-side filter of the listed accounts.
+	Filter *AccountFilter
+}
+
+func (o ListOptions) queryParams() (map[string]string, error) {
+	queryParams := make(map[string]string)
+
+	if o.Filter != nil {
+		var err error
+		if queryParams, err = o.Filter.queryParams(); err != nil {
+			return nil, err
+		}
+	}
+
+	queryParams["page[number]"] = fmt.Sprint(o.PageNumber)
+
+	if o.PageSize > 0 {
+		queryParams["page[size]"] = fmt.Sprint(o.PageSize)
+	}
+
+	return queryParams, nil
+}
+
+// AccountIterator iterates over a paginated list of accounts.
+// Pages are fetched lazily, i.e. only when the iteration goes past the last account of the current page.
+//
+// Typical usage:
+//
+//	it := client.Accounts().List(ctx, form3apiclient.ListOptions{PageSize: 100})
+//	for it.Next() {
+//		accountData := it.Account()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+type AccountIterator struct {
+	*restresourcehandler.Iterator[AccountData]
+}
+
+// Account returns the account the iterator currently points at.
+func (it *AccountIterator) Account() AccountData {
+	return it.Value()
+}
@@ -0,0 +1,275 @@
+package form3apiclient
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/form3apiclient/iban"
+)
+
+// ValidationError is a problem found in a single field of an account (see ValidateAccount).
+type ValidationError struct {
+	// Field is the JSON path of the invalid field (e.g. "attributes.bank_id").
+	Field string
+	// Message describes the problem (e.g. "must be 6 digits").
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// presence denotes if a field is required, optional or not supported in a country.
+type presence int
+
+const (
+	optional presence = iota
+	required
+	notSupported
+)
+
+// fieldRule describes a field of an account in a country.
+type fieldRule struct {
+	presence presence
+	format   *regexp.Regexp
+	// description describes the format, e.g. "6 digits"
+	description string
+}
+
+// countryRules are the local account rules of a country
+// (see https://api-docs.form3.tech/api.html#organisation-accounts-create).
+type countryRules struct {
+	bankID        fieldRule
+	bankIDCode    BankIDCode // empty - not supported
+	bic           presence
+	accountNumber fieldRule
+	iban          presence
+}
+
+func digits(count string) fieldRule {
+	return fieldRule{
+		format:      regexp.MustCompile(`^[0-9]{` + count + `}$`),
+		description: strings.Replace(count, ",", "-", 1) + " digits",
+	}
+}
+
+func characters(count string) fieldRule {
+	return fieldRule{
+		format:      regexp.MustCompile(`^[A-Z0-9]{` + count + `}$`),
+		description: strings.Replace(count, ",", "-", 1) + " characters",
+	}
+}
+
+func (r fieldRule) with(p presence) fieldRule {
+	r.presence = p
+
+	return r
+}
+
+// australianAccountNumber is the format of AU account numbers, which cannot start with 0.
+var australianAccountNumber = fieldRule{
+	format:      regexp.MustCompile(`^[1-9][0-9]{5,9}$`),
+	description: "6-10 digits not starting with 0",
+}
+
+// accountRules are the local account rules of the supported countries.
+var accountRules = map[string]countryRules{
+	"AU": {
+		bankID:        digits("6"),
+		bankIDCode:    BankIDCodeAustralia,
+		bic:           required,
+		accountNumber: australianAccountNumber,
+		iban:          notSupported,
+	},
+	"BE": {
+		bankID:        digits("3").with(required),
+		bankIDCode:    BankIDCodeBelgium,
+		accountNumber: digits("7"),
+	},
+	"CA": {
+		bankID:        fieldRule{format: regexp.MustCompile(`^0[0-9]{8}$`), description: "9 digits starting with 0"},
+		bankIDCode:    BankIDCodeCanada,
+		bic:           required,
+		accountNumber: digits("7,12"),
+		iban:          notSupported,
+	},
+	"CH": {
+		bankID:        digits("5").with(required),
+		bankIDCode:    BankIDCodeSwitzerland,
+		accountNumber: characters("12"),
+	},
+	"DE": {
+		bankID:        digits("8").with(required),
+		bankIDCode:    BankIDCodeGermany,
+		accountNumber: digits("7"),
+	},
+	"ES": {
+		bankID:        digits("8").with(required),
+		bankIDCode:    BankIDCodeSpain,
+		accountNumber: digits("10"),
+	},
+	"FR": {
+		bankID:        digits("10").with(required),
+		bankIDCode:    BankIDCodeFrance,
+		accountNumber: characters("11"),
+	},
+	"GB": {
+		bankID:        digits("6").with(required),
+		bankIDCode:    BankIDCodeUK,
+		bic:           required,
+		accountNumber: digits("8"),
+	},
+	"GR": {
+		bankID:        digits("7").with(required),
+		bankIDCode:    BankIDCodeGreece,
+		accountNumber: digits("16"),
+	},
+	"HK": {
+		bankID:        digits("3"),
+		bankIDCode:    BankIDCodeHongKong,
+		bic:           required,
+		accountNumber: digits("9,12"),
+		iban:          notSupported,
+	},
+	"IT": {
+		bankID:        digits("10,11").with(required),
+		bankIDCode:    BankIDCodeItaly,
+		accountNumber: characters("12"),
+	},
+	"LU": {
+		bankID:        digits("3").with(required),
+		bankIDCode:    BankIDCodeLuxembourg,
+		accountNumber: characters("13"),
+	},
+	"NL": {
+		bankID:        fieldRule{presence: notSupported},
+		bic:           required,
+		accountNumber: digits("10"),
+	},
+	"PL": {
+		bankID:        digits("8").with(required),
+		bankIDCode:    BankIDCodePoland,
+		accountNumber: digits("16"),
+	},
+	"PT": {
+		bankID:        digits("8").with(required),
+		bankIDCode:    BankIDCodePortugal,
+		accountNumber: digits("11"),
+	},
+	"US": {
+		bankID:        digits("9").with(required),
+		bankIDCode:    BankIDCodeUSA,
+		bic:           required,
+		accountNumber: characters("6,17"),
+		iban:          notSupported,
+	},
+}
+
+// ValidateAccount checks the account against the local account rules of its country
+// (e.g. a GB account needs a 6-digit bank id, bank id code GBDSC, a BIC and an 8-digit account number if it is set).
+// The BIC is validated (see ParseBic) and checked against the country of the account.
+// The IBAN is validated (see iban.Parse) and cross-checked against the other attributes (see AccountData.WithIban).
+// Unknown account classifications and statuses (see Classification.IsKnown) are reported as well.
+// Returns the problems found in the fields of the account (empty if the account is valid).
+// See WithAccountValidation to validate the created accounts before sending them.
+func ValidateAccount(account AccountData) []ValidationError {
+	var errs []ValidationError
+
+	report := func(field string, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{"attributes." + field, fmt.Sprintf(format, args...)})
+	}
+
+	attributes := account.Attributes
+
+	if attributes.AccountClassification != "" && !attributes.AccountClassification.IsKnown() {
+		report("account_classification", `"%s" is not a known account classification`, attributes.AccountClassification)
+	}
+
+	if attributes.Status != "" && !attributes.Status.IsKnown() {
+		report("status", `"%s" is not a known account status`, attributes.Status)
+	}
+
+	if len(attributes.Name) == 0 || strings.TrimSpace(attributes.Name[0]) == "" {
+		report("name", "is required")
+	}
+
+	rules, ok := accountRules[attributes.Country]
+
+	switch {
+	case attributes.Country == "":
+		report("country", "is required")
+
+		return errs
+	case !ok:
+		report("country", `"%s" is not supported`, attributes.Country)
+
+		return errs
+	}
+
+	validateField(report, "bank_id", attributes.BankID, rules.bankID, attributes.Country)
+
+	switch {
+	case rules.bankIDCode == "" && attributes.BankIDCode != "":
+		report("bank_id_code", "is not supported in %s", attributes.Country)
+	case rules.bankIDCode != "" && attributes.BankIDCode != rules.bankIDCode:
+		report("bank_id_code", "must be %s", rules.bankIDCode)
+	}
+
+	validateField(report, "bic", attributes.Bic.String(), fieldRule{presence: rules.bic}, attributes.Country)
+
+	if attributes.Bic != "" && rules.bic != notSupported {
+		validateBic(report, attributes)
+	}
+
+	validateField(report, "account_number", attributes.AccountNumber, rules.accountNumber, attributes.Country)
+	validateField(report, "iban", attributes.Iban, fieldRule{presence: rules.iban}, attributes.Country)
+
+	if attributes.Iban != "" && rules.iban != notSupported {
+		validateIban(report, attributes)
+	}
+
+	return errs
+}
+
+func validateBic(report func(field string, format string, args ...interface{}), attributes AccountAttributes) {
+	parsed, err := ParseBic(attributes.Bic.String())
+
+	switch {
+	case err != nil:
+		report("bic", "must be a valid BIC of 8 or 11 characters")
+	case parsed != attributes.Bic:
+		report("bic", "must be upper case without surrounding spaces (%s)", parsed)
+	case parsed.CountryCode() != attributes.Country:
+		report("bic", "does not match country")
+	}
+}
+
+func validateIban(report func(field string, format string, args ...interface{}), attributes AccountAttributes) {
+	parsed, err := iban.Parse(attributes.Iban)
+	if err != nil {
+		report("iban", "must be a valid IBAN")
+
+		return
+	}
+
+	if mismatch := ibanMismatch(parsed, attributes); mismatch != "" {
+		report("iban", mismatch)
+	}
+}
+
+func validateField(
+	report func(field string, format string, args ...interface{}),
+	field string,
+	value string,
+	rule fieldRule,
+	country string) {
+	switch {
+	case value == "" && rule.presence == required:
+		report(field, "is required in %s", country)
+	case value != "" && rule.presence == notSupported:
+		report(field, "is not supported in %s", country)
+	case value != "" && rule.format != nil && !rule.format.MatchString(value):
+		report(field, "must be %s", rule.description)
+	}
+}
@@ -0,0 +1,198 @@
+package form3apiclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/form3apiclient"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+func localAccount(
+	country string,
+	bankID string,
+	bankIDCode form3apiclient.BankIDCode,
+	bic form3apiclient.Bic,
+	accountNumber string) form3apiclient.AccountData {
+	account := someValidAccountData(someValidUUID)
+	account.Attributes.Country = country
+	account.Attributes.BankID = bankID
+	account.Attributes.BankIDCode = bankIDCode
+	account.Attributes.Bic = bic
+	account.Attributes.AccountNumber = accountNumber
+
+	return account
+}
+
+func validGBAccount() form3apiclient.AccountData {
+	return localAccount("GB", "400300", form3apiclient.BankIDCodeUK, "NWBKGB22", "41426819")
+}
+
+var _ = Describe("ValidateAccount", func() {
+	DescribeTable("accepts valid account",
+		func(account form3apiclient.AccountData) {
+			Expect(form3apiclient.ValidateAccount(account)).To(BeEmpty())
+		},
+		Entry("AU", localAccount("AU", "123456", form3apiclient.BankIDCodeAustralia, "NWBKAU22", "1234567")),
+		Entry("BE", localAccount("BE", "123", form3apiclient.BankIDCodeBelgium, "", "1234567")),
+		Entry("CA", localAccount("CA", "012345678", form3apiclient.BankIDCodeCanada, "NWBKCA22", "1234567")),
+		Entry("CH", localAccount("CH", "12345", form3apiclient.BankIDCodeSwitzerland, "", "12345678901A")),
+		Entry("DE", localAccount("DE", "12345678", form3apiclient.BankIDCodeGermany, "", "1234567")),
+		Entry("ES", localAccount("ES", "12345678", form3apiclient.BankIDCodeSpain, "", "1234567890")),
+		Entry("FR", localAccount("FR", "2004101005", form3apiclient.BankIDCodeFrance, "", "0500013M026")),
+		Entry("FR with iban",
+			func() form3apiclient.AccountData {
+				account := localAccount("FR", "2004101005", form3apiclient.BankIDCodeFrance, "", "0500013M026")
+				account.Attributes.Iban = "FR1420041010050500013M02606"
+
+				return account
+			}()),
+		Entry("GB", validGBAccount()),
+		Entry("GB without account number", localAccount("GB", "400300", form3apiclient.BankIDCodeUK, "NWBKGB22", "")),
+		Entry("GR", localAccount("GR", "1234567", form3apiclient.BankIDCodeGreece, "", "1234567890123456")),
+		Entry("HK", localAccount("HK", "", form3apiclient.BankIDCodeHongKong, "NWBKHKHHXXX", "123456789")),
+		Entry("IT", localAccount("IT", "0123456789", form3apiclient.BankIDCodeItaly, "", "000000123456")),
+		Entry("LU", localAccount("LU", "123", form3apiclient.BankIDCodeLuxembourg, "", "1234567890123")),
+		Entry("NL", localAccount("NL", "", "", "ABNANL2A", "0417164300")),
+		Entry("PL", localAccount("PL", "12345678", form3apiclient.BankIDCodePoland, "", "1234567890123456")),
+		Entry("PT", localAccount("PT", "12345678", form3apiclient.BankIDCodePortugal, "", "12345678901")),
+		Entry("US", localAccount("US", "123456789", form3apiclient.BankIDCodeUSA, "NWBKUS22", "123456")),
+	)
+
+	DescribeTable("reports invalid fields",
+		func(account form3apiclient.AccountData, expectedErrors ...form3apiclient.ValidationError) {
+			Expect(form3apiclient.ValidateAccount(account)).To(Equal(expectedErrors))
+		},
+		Entry("missing name",
+			func() form3apiclient.AccountData {
+				account := validGBAccount()
+				account.Attributes.Name = nil
+
+				return account
+			}(),
+			form3apiclient.ValidationError{Field: "attributes.name", Message: "is required"}),
+		Entry("unknown classification and status",
+			func() form3apiclient.AccountData {
+				account := validGBAccount()
+				account.Attributes.AccountClassification = "Charity"
+				account.Attributes.Status = "frozen"
+
+				return account
+			}(),
+			form3apiclient.ValidationError{
+				Field:   "attributes.account_classification",
+				Message: `"Charity" is not a known account classification`,
+			},
+			form3apiclient.ValidationError{Field: "attributes.status", Message: `"frozen" is not a known account status`}),
+		Entry("unknown bank id code",
+			localAccount("GB", "400300", "GBDCS", "NWBKGB22", "41426819"),
+			form3apiclient.ValidationError{Field: "attributes.bank_id_code", Message: "must be GBDSC"}),
+		Entry("missing country",
+			localAccount("", "400300", form3apiclient.BankIDCodeUK, "NWBKGB22", "41426819"),
+			form3apiclient.ValidationError{Field: "attributes.country", Message: "is required"}),
+		Entry("unsupported country",
+			localAccount("XX", "400300", form3apiclient.BankIDCodeUK, "NWBKGB22", "41426819"),
+			form3apiclient.ValidationError{Field: "attributes.country", Message: `"XX" is not supported`}),
+		Entry("GB with invalid bank id, bank id code and account number",
+			localAccount("GB", "40030", "GBDCS", "NWBKGB22", "4142681"),
+			form3apiclient.ValidationError{Field: "attributes.bank_id", Message: "must be 6 digits"},
+			form3apiclient.ValidationError{Field: "attributes.bank_id_code", Message: "must be GBDSC"},
+			form3apiclient.ValidationError{Field: "attributes.account_number", Message: "must be 8 digits"}),
+		Entry("GB without bank id and bic",
+			localAccount("GB", "", form3apiclient.BankIDCodeUK, "", ""),
+			form3apiclient.ValidationError{Field: "attributes.bank_id", Message: "is required in GB"},
+			form3apiclient.ValidationError{Field: "attributes.bic", Message: "is required in GB"}),
+		Entry("GB with invalid bic",
+			localAccount("GB", "400300", form3apiclient.BankIDCodeUK, "NWBK", ""),
+			form3apiclient.ValidationError{Field: "attributes.bic", Message: "must be a valid BIC of 8 or 11 characters"}),
+		Entry("GB with bic of other country",
+			localAccount("GB", "400300", form3apiclient.BankIDCodeUK, "DEUTDEFF", ""),
+			form3apiclient.ValidationError{Field: "attributes.bic", Message: "does not match country"}),
+		Entry("GB with lower case bic",
+			localAccount("GB", "400300", form3apiclient.BankIDCodeUK, "nwbkgb22", ""),
+			form3apiclient.ValidationError{
+				Field:   "attributes.bic",
+				Message: "must be upper case without surrounding spaces (NWBKGB22)",
+			}),
+		Entry("DE with invalid BLZ",
+			localAccount("DE", "1234567", form3apiclient.BankIDCodeGermany, "", ""),
+			form3apiclient.ValidationError{Field: "attributes.bank_id", Message: "must be 8 digits"}),
+		Entry("FR with account number of 10 characters",
+			localAccount("FR", "2004101005", form3apiclient.BankIDCodeFrance, "", "0500013M02"),
+			form3apiclient.ValidationError{Field: "attributes.account_number", Message: "must be 11 characters"}),
+		Entry("NL with bank id and bank id code",
+			localAccount("NL", "123", form3apiclient.BankIDCodeGermany, "ABNANL2A", ""),
+			form3apiclient.ValidationError{Field: "attributes.bank_id", Message: "is not supported in NL"},
+			form3apiclient.ValidationError{Field: "attributes.bank_id_code", Message: "is not supported in NL"}),
+		Entry("AU with account number starting with 0",
+			localAccount("AU", "", form3apiclient.BankIDCodeAustralia, "NWBKAU22", "0123456"),
+			form3apiclient.ValidationError{
+				Field: "attributes.account_number", Message: "must be 6-10 digits not starting with 0",
+			}),
+		Entry("US with iban",
+			func() form3apiclient.AccountData {
+				account := localAccount("US", "123456789", form3apiclient.BankIDCodeUSA, "NWBKUS22", "")
+				account.Attributes.Iban = "GB11NWBK40030041426819"
+
+				return account
+			}(),
+			form3apiclient.ValidationError{Field: "attributes.iban", Message: "is not supported in US"}),
+		Entry("GB with invalid iban",
+			func() form3apiclient.AccountData {
+				account := validGBAccount()
+				account.Attributes.Iban = "GB11"
+
+				return account
+			}(),
+			form3apiclient.ValidationError{Field: "attributes.iban", Message: "must be a valid IBAN"}),
+	)
+
+	Context("when creating account", func() {
+		var server *ghttp.Server
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("does not send invalid account", func() {
+			client := form3apiclient.MustNew(server.URL(), form3apiclient.WithAccountValidation())
+
+			_, err := client.Accounts().Create(
+				context.Background(), localAccount("GB", "40030", form3apiclient.BankIDCodeUK, "NWBKGB22", ""))
+
+			Expect(err).To(MatchError(form3apiclient.ErrInvalidAccount))
+			Expect(err).To(MatchError("invalid account: attributes.bank_id must be 6 digits"))
+			var invalidAccountErr *form3apiclient.InvalidAccountError
+			Expect(errors.As(err, &invalidAccountErr)).To(BeTrue())
+			Expect(invalidAccountErr.Errors).To(HaveLen(1))
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("sends valid account", func() {
+			client := form3apiclient.MustNew(server.URL(), form3apiclient.WithAccountValidation())
+			server.AppendHandlers(ghttp.RespondWithJSONEncoded(http.StatusCreated, wrapper{validGBAccount()}))
+
+			_, err := client.Accounts().Create(context.Background(), validGBAccount())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("does not validate account by default", func() {
+			client := form3apiclient.MustNew(server.URL())
+			server.AppendHandlers(ghttp.RespondWithJSONEncoded(http.StatusCreated, wrapper{validGBAccount()}))
+
+			_, err := client.Accounts().Create(
+				context.Background(), localAccount("GB", "40030", form3apiclient.BankIDCodeUK, "NWBKGB22", ""))
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
@@ -2,8 +2,9 @@ package form3apiclient
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"net/http"
+	"reflect"
 
 	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
 )
@@ -21,46 +22,310 @@ type Accounts interface {
 	// Create creates an account using the passed in AccountData DTO instance.
 	// Returns the created account instance.
 	// Context can be used to control asynchronous requests.
+	// The default organisation id of the client is used if accountData has no organisation id set
+	// (see WithDefaultOrganisationID).
+	// If the server reports a conflict (e.g. because a timed out attempt has in fact created the account),
+	// the account with the id of accountData is fetched. It is returned if it has the same attributes
+	// as accountData. Otherwise an error wrapping ErrDuplicateConflict is returned.
+	// The account is validated before being sent if the client has been constructed with WithAccountValidation.
 	Create(ctx context.Context, accountData AccountData) (AccountData, error)
+
+	// Update changes the attributes of an account with the given id and version.
+	// Only the attributes set in changes are sent to the server.
+	// Returns the updated account instance (with the bumped version).
+	// Returns an error wrapping ErrVersionConflict if the account has been changed in the meantime
+	// (i.e. the version is not the current version of the account).
+	// Context can be used to control asynchronous requests.
+	Update(ctx context.Context, id string, version int64, changes AccountChanges) (AccountData, error)
+
+	// GetResponse is like Get, but returns the account data along with the response metadata
+	// (e.g. the request id, also if the API returned an error response).
+	GetResponse(ctx context.Context, id string) (AccountResponse, error)
+
+	// DeleteResponse is like Delete, but also returns the response metadata.
+	DeleteResponse(ctx context.Context, id string, version int64) (ResponseMetadata, error)
+
+	// CreateResponse is like Create, but returns the created account along with the response metadata.
+	CreateResponse(ctx context.Context, accountData AccountData) (AccountResponse, error)
+
+	// UpdateResponse is like Update, but returns the updated account along with the response metadata.
+	UpdateResponse(ctx context.Context, id string, version int64, changes AccountChanges) (AccountResponse, error)
+
+	// List lists accounts page by page according to the given options.
+	// Accounts can be filtered on the server side (see AccountFilter).
+	// Returns an iterator which fetches the pages lazily, following the "next" links sent by the server.
+	// The response metadata of the pages is available via AccountIterator.PageMetadata.
+	// Context can be used to control asynchronous requests of all page fetches.
+	List(ctx context.Context, options ListOptions) *AccountIterator
 }
 
 func (a *accounts) Get(ctx context.Context, accountID string) (AccountData, error) {
-	var accountData AccountData
-	err := a.Handler.Fetch(ctx, accountID, nil, &accountData)
+	response, err := a.GetResponse(ctx, accountID)
+
+	return response.Data, err
+}
 
-	return accountData, err //nolint:wrapcheck // this error is in fact local (see extractRemoteError)
+func (a *accounts) GetResponse(ctx context.Context, accountID string) (AccountResponse, error) {
+	response, err := a.Resource.GetResponse(ctx, accountID)
+
+	return response, err //nolint:wrapcheck // this error is in fact local (see extractRemoteError)
 }
 
 func (a *accounts) Delete(ctx context.Context, accountID string, version int64) error {
-	err := a.Handler.Delete(ctx, accountID, map[string]string{"version": fmt.Sprint(version)})
+	_, err := a.DeleteResponse(ctx, accountID, version)
+
+	return err
+}
+
+func (a *accounts) DeleteResponse(ctx context.Context, accountID string, version int64) (ResponseMetadata, error) {
+	metadata, err := a.Resource.DeleteResponse(ctx, accountID, map[string]string{"version": fmt.Sprint(version)})
 
-	return err //nolint:wrapcheck // this error is in fact local (see extractRemoteError)
+	return metadata, err //nolint:wrapcheck // this error is in fact local (see extractRemoteError)
 }
 
 // Create creates an account using the passed in AccountData DTO instance.
 // Returns the created account instance.
 // Context can be used to control asynchronous requests.
+//
+// If the API reports a conflict, the existing account of the same id is returned
+// if all the attributes sent in the request match it. Attributes with zero values are not sent
+// (e.g. JointAccount set to false), so they are not compared. Otherwise an error wrapping ErrDuplicateConflict
+// is returned.
 func (a *accounts) Create(ctx context.Context, accountData AccountData) (AccountData, error) {
-	var response AccountData
-	err := a.Handler.Create(ctx, &accountData, &response)
+	response, err := a.CreateResponse(ctx, accountData)
+
+	return response.Data, err
+}
+
+func (a *accounts) CreateResponse(ctx context.Context, accountData AccountData) (AccountResponse, error) {
+	if a.IsValidatedOnCreate {
+		if errs := ValidateAccount(accountData); len(errs) > 0 {
+			return AccountResponse{}, &InvalidAccountError{errs}
+		}
+	}
+
+	if accountData.OrganisationID == "" {
+		accountData.OrganisationID = a.DefaultOrganisationID
+	}
+
+	response, err := a.Resource.CreateResponse(ctx, accountData)
+
+	if IsConflict(err) && accountData.ID != "" {
+		return a.reconcileConflict(ctx, accountData, err)
+	}
 
 	return response, err //nolint:wrapcheck // this error is in fact local (see extractRemoteError)
 }
 
+// reconcileConflict resolves a conflict reported by the server when creating the given account
+// by comparing the account with the existing account of the same id.
+func (a *accounts) reconcileConflict(
+	ctx context.Context,
+	accountData AccountData,
+	conflictErr error) (AccountResponse, error) {
+	existing, err := a.Resource.GetResponse(ctx, accountData.ID)
+
+	switch {
+	case IsNotFound(err):
+		// the conflict is not caused by the id (e.g. a duplicate account number)
+		return AccountResponse{}, conflictErr
+	case err != nil:
+		return AccountResponse{}, WrapError(err, "fetching conflicting account")
+	}
+
+	isSame, err := isSameAccount(accountData, existing.Data)
+	if err != nil {
+		return AccountResponse{}, err
+	}
+
+	if !isSame {
+		return AccountResponse{}, DuplicateConflictError(conflictErr)
+	}
+
+	return existing, nil
+}
+
+// isSameAccount reports whether the existing account matches the requested one,
+// i.e. if all the properties sent in the request are equal to the properties of the existing account
+// (the properties not sent may have been defaulted by the server).
+// The accounts are compared in their JSON form, so properties with zero values, which are not sent
+// (e.g. JointAccount set to false), are not compared.
+func isSameAccount(requested AccountData, existing AccountData) (bool, error) {
+	requestedJSON, err := jsonValue(requested)
+	if err != nil {
+		return false, err
+	}
+
+	existing, isComparable := withoutUnknownValues(existing, requested)
+	if !isComparable {
+		return false, nil
+	}
+
+	existingJSON, err := jsonValue(existing)
+	if err != nil {
+		return false, err
+	}
+
+	return isSubsetOf(requestedJSON, existingJSON), nil
+}
+
+// withoutUnknownValues clears the values unknown to this package (which cannot be encoded)
+// in the existing account, so that it can be compared with the requested one.
+// Returns false if such a value is set in the requested account, i.e. the accounts differ.
+func withoutUnknownValues(existing AccountData, requested AccountData) (AccountData, bool) {
+	attributes := &existing.Attributes
+
+	return existing, clearUnknownValue(&attributes.AccountClassification, requested.Attributes.AccountClassification) &&
+		clearUnknownValue(&attributes.Status, requested.Attributes.Status) &&
+		clearUnknownValue(&attributes.BankIDCode, requested.Attributes.BankIDCode)
+}
+
+// clearUnknownValue clears the existing value if it is unknown and not requested.
+// Returns false if it is unknown and requested, i.e. differs from the requested (known) value.
+func clearUnknownValue[T interface {
+	~string
+	IsKnown() bool
+}](existing *T, requested T) bool {
+	if *existing == "" || (*existing).IsKnown() {
+		return true
+	}
+
+	if requested != "" {
+		return false
+	}
+
+	*existing = ""
+
+	return true
+}
+
+// jsonValue converts the given account to its generic JSON representation.
+func jsonValue(accountData AccountData) (interface{}, error) {
+	content, err := json.Marshal(accountData)
+	if err != nil {
+		return nil, WrapError(err, "encoding account json")
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(content, &value); err != nil {
+		return nil, WrapError(err, "decoding account json")
+	}
+
+	return value, nil
+}
+
+// isSubsetOf reports whether all the properties of the subset JSON value are present
+// with equal values in the set JSON value. Values other than objects have to be equal.
+func isSubsetOf(subset interface{}, set interface{}) bool {
+	subsetObject, isObject := subset.(map[string]interface{})
+	if !isObject {
+		return reflect.DeepEqual(subset, set)
+	}
+
+	setObject, isObject := set.(map[string]interface{})
+	if !isObject {
+		return false
+	}
+
+	for key, value := range subsetObject {
+		if !isSubsetOf(value, setObject[key]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// accountIdempotencyKey is a restresourcehandler.IdempotencyKeyFunc using the (client-generated) id
+// of the created account as the idempotency key.
+func accountIdempotencyKey(resource interface{}) (string, error) {
+	if accountData, ok := resource.(*AccountData); ok && accountData.ID != "" {
+		return accountData.ID, nil
+	}
+
+	return restresourcehandler.RandomIdempotencyKey(resource)
+}
+
+func (a *accounts) Update(
+	ctx context.Context,
+	accountID string,
+	version int64,
+	changes AccountChanges) (AccountData, error) {
+	response, err := a.UpdateResponse(ctx, accountID, version, changes)
+
+	return response.Data, err
+}
+
+func (a *accounts) UpdateResponse(
+	ctx context.Context,
+	accountID string,
+	version int64,
+	changes AccountChanges) (AccountResponse, error) {
+	patch := accountPatch{
+		ID:         accountID,
+		Type:       accountsResourceType,
+		Version:    version,
+		Attributes: changes,
+	}
+
+	response, err := a.Resource.PatchResponse(ctx, accountID, &patch)
+
+	if IsConflict(err) {
+		return response, VersionConflictError(err)
+	}
+
+	return response, err //nolint:wrapcheck // this error is in fact local (see extractRemoteError)
+}
+
+func (a *accounts) List(ctx context.Context, options ListOptions) *AccountIterator {
+	queryParams, err := options.queryParams()
+	if err != nil {
+		return &AccountIterator{restresourcehandler.NewFailedIterator[AccountData](err)}
+	}
+
+	return &AccountIterator{a.Resource.List(ctx, queryParams)}
+}
+
 type accounts struct {
-	Handler *restresourcehandler.RestResourceHandler
+	Resource              *restresourcehandler.TypedResource[AccountData]
+	DefaultOrganisationID string
+	IsValidatedOnCreate   bool
 }
 
-const resourcePath = "organisation/accounts"
+const (
+	resourcePath         = "organisation/accounts"
+	accountsResourceType = "accounts"
+)
 
-func newAccounts(apiURL string, httpClient *http.Client) (*accounts, error) {
+func newAccounts(apiURL string, options options) (*accounts, error) {
 	accountsResourceURL, err := join(apiURL, resourcePath)
 	if err != nil {
 		return nil, WrapError(err, "constructing api url")
 	}
 
-	handler := restresourcehandler.NewRestResourceHandler(
-		httpClient, accountsResourceURL, getRestResourceHandlerConfig())
+	config := getRestResourceHandlerConfig(options)
+	config.ResourceName = accountsResourceType
+
+	if options.circuitBreaker != nil {
+		breakerConfig := *options.circuitBreaker
+		if breakerConfig.Name == "" {
+			breakerConfig.Name = resourcePath
+		}
+
+		config.CircuitBreaker, err = restresourcehandler.NewCircuitBreaker(breakerConfig)
+		if err != nil {
+			return nil, WrapError(err, "constructing accounts circuit breaker")
+		}
+	}
+
+	handler, err := restresourcehandler.New(options.httpClient, accountsResourceURL, config)
+	if err != nil {
+		return nil, WrapError(err, "constructing accounts resource handler")
+	}
 
-	return &accounts{handler}, nil
+	return &accounts{
+		restresourcehandler.NewTypedResource[AccountData](handler),
+		options.defaultOrganisationID,
+		options.isAccountValidated,
+	}, nil
 }
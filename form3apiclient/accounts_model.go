@@ -1,32 +1,165 @@
 package form3apiclient
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // AccountData is a DTO representing an account in the Form3 org section.
 // See https://api-docs.form3.tech/api.html#organisation-accounts for
 // more information about the model.
 type AccountData struct {
-	Attributes     AccountAttributes `json:"attributes,omitempty"`
-	ID             string            `json:"id,omitempty"`
-	OrganisationID string            `json:"organisation_id,omitempty"`
-	Type           string            `json:"type,omitempty"`
-	Version        int64             `json:"version,omitempty"`
+	Attributes     AccountAttributes     `json:"attributes,omitempty"`
+	CreatedOn      *time.Time            `json:"created_on,omitempty"`
+	ID             string                `json:"id,omitempty"`
+	ModifiedOn     *time.Time            `json:"modified_on,omitempty"`
+	OrganisationID string                `json:"organisation_id,omitempty"`
+	Relationships  *AccountRelationships `json:"relationships,omitempty"`
+	Type           string                `json:"type,omitempty"`
+	Version        int64                 `json:"version,omitempty"`
 }
 
 // AccountAttributes is a sub-section of the information about an account.
 // Part of AccountData DTO.
 type AccountAttributes struct {
-	AccountClassification   string   `json:"account_classification,omitempty"`
-	AccountMatchingOptOut   bool     `json:"account_matching_opt_out,omitempty"`
-	AccountNumber           string   `json:"account_number,omitempty"`
-	AlternativeNames        []string `json:"alternative_names,omitempty"`
-	BankID                  string   `json:"bank_id,omitempty"`
-	BankIDCode              string   `json:"bank_id_code,omitempty"`
-	BaseCurrency            string   `json:"base_currency,omitempty"`
-	Bic                     string   `json:"bic,omitempty"`
-	Country                 string   `json:"country,omitempty"`
-	Iban                    string   `json:"iban,omitempty"`
-	JointAccount            bool     `json:"joint_account,omitempty"`
-	Name                    []string `json:"name,omitempty"`
-	SecondaryIdentification string   `json:"secondary_identification,omitempty"`
-	Status                  string   `json:"status,omitempty"`
-	Switched                bool     `json:"switched,omitempty"`
+	AcceptanceQualifier        string                      `json:"acceptance_qualifier,omitempty"`
+	AccountClassification      Classification              `json:"account_classification,omitempty"`
+	AccountMatchingOptOut      bool                        `json:"account_matching_opt_out,omitempty"`
+	AccountNumber              string                      `json:"account_number,omitempty"`
+	AlternativeNames           []string                    `json:"alternative_names,omitempty"`
+	BankID                     string                      `json:"bank_id,omitempty"`
+	BankIDCode                 BankIDCode                  `json:"bank_id_code,omitempty"`
+	BaseCurrency               string                      `json:"base_currency,omitempty"`
+	Bic                        Bic                         `json:"bic,omitempty"`
+	Country                    string                      `json:"country,omitempty"`
+	CustomerID                 string                      `json:"customer_id,omitempty"`
+	Iban                       string                      `json:"iban,omitempty"`
+	JointAccount               bool                        `json:"joint_account,omitempty"`
+	Name                       []string                    `json:"name,omitempty"`
+	OrganisationIdentification *OrganisationIdentification `json:"organisation_identification,omitempty"`
+	PrivateIdentification      *PrivateIdentification      `json:"private_identification,omitempty"`
+	ProcessingService          string                      `json:"processing_service,omitempty"`
+	ReferenceMask              string                      `json:"reference_mask,omitempty"`
+	SecondaryIdentification    string                      `json:"secondary_identification,omitempty"`
+	Status                     AccountStatus               `json:"status,omitempty"`
+	StatusReason               string                      `json:"status_reason,omitempty"`
+	Switched                   bool                        `json:"switched,omitempty"`
+	UserDefinedData            []UserDefinedData           `json:"user_defined_data,omitempty"`
+	ValidationType             string                      `json:"validation_type,omitempty"`
+}
+
+// PrivateIdentification identifies the person holding a personal account.
+// Part of AccountAttributes.
+type PrivateIdentification struct {
+	Address        []string `json:"address,omitempty"`
+	BirthCountry   string   `json:"birth_country,omitempty"`
+	BirthDate      *Date    `json:"birth_date,omitempty"`
+	City           string   `json:"city,omitempty"`
+	Country        string   `json:"country,omitempty"`
+	Identification string   `json:"identification,omitempty"`
+}
+
+// OrganisationIdentification identifies the organisation holding a business account.
+// Part of AccountAttributes.
+type OrganisationIdentification struct {
+	Actors         []OrganisationActor `json:"actors,omitempty"`
+	Address        []string            `json:"address,omitempty"`
+	City           string              `json:"city,omitempty"`
+	Country        string              `json:"country,omitempty"`
+	Identification string              `json:"identification,omitempty"`
+}
+
+// OrganisationActor is a person acting on behalf of an organisation (e.g. a director).
+// Part of OrganisationIdentification.
+type OrganisationActor struct {
+	BirthDate *Date    `json:"birth_date,omitempty"`
+	Name      []string `json:"name,omitempty"`
+	Residency string   `json:"residency,omitempty"`
+}
+
+// UserDefinedData is a custom key-value pair stored with an account.
+// Part of AccountAttributes.
+type UserDefinedData struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// AccountRelationships are the resources related to an account.
+// Part of AccountData DTO.
+type AccountRelationships struct {
+	AccountEvents *RelationshipData `json:"account_events,omitempty"`
+	MasterAccount *RelationshipData `json:"master_account,omitempty"`
+}
+
+// RelationshipData lists the resources of a relationship (see AccountRelationships).
+type RelationshipData struct {
+	Data []ResourceIdentifier `json:"data"`
+}
+
+// ResourceIdentifier identifies a related resource (e.g. the master account).
+type ResourceIdentifier struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// dateLayout is the layout of the dates sent by the API (e.g. "2017-07-23").
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date without time (e.g. a birth date), encoded as "YYYY-MM-DD".
+type Date struct {
+	time.Time
+}
+
+// NewDate constructs a Date for the given day.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// String returns the date in the "YYYY-MM-DD" format.
+func (d Date) String() string {
+	return d.Format(dateLayout)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String()) //nolint:wrapcheck // marshalling a string does not fail
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return WrapError(err, "decoding date")
+	}
+
+	parsed, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return WrapError(err, "decoding date")
+	}
+
+	d.Time = parsed
+
+	return nil
+}
+
+// AccountChanges represents changes of the attributes of an existing account (see Accounts.Update).
+// Only the properties which are set (not nil) are sent to the server and changed.
+// Name and AlternativeNames can be cleared by setting them to (pointers to) empty slices.
+type AccountChanges struct {
+	AccountClassification   *Classification `json:"account_classification,omitempty"`
+	AccountMatchingOptOut   *bool           `json:"account_matching_opt_out,omitempty"`
+	AlternativeNames        *[]string       `json:"alternative_names,omitempty"`
+	JointAccount            *bool           `json:"joint_account,omitempty"`
+	Name                    *[]string       `json:"name,omitempty"`
+	SecondaryIdentification *string         `json:"secondary_identification,omitempty"`
+	Status                  *AccountStatus  `json:"status,omitempty"`
+	Switched                *bool           `json:"switched,omitempty"`
+}
+
+// accountPatch is the DTO sent to the server in order to update an account.
+type accountPatch struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	Version    int64          `json:"version"`
+	Attributes AccountChanges `json:"attributes"`
 }
@@ -0,0 +1,85 @@
+package form3apiclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/form3apiclient"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+// readGoldenAccount reads the "data" of a recorded API payload from testdata.
+func readGoldenAccount(name string) []byte {
+	payload, err := os.ReadFile(filepath.Join("testdata", name))
+	Expect(err).NotTo(HaveOccurred())
+
+	var recorded struct {
+		Data json.RawMessage `json:"data"`
+	}
+	Expect(json.Unmarshal(payload, &recorded)).To(Succeed())
+
+	return recorded.Data
+}
+
+var _ = Describe("AccountData", func() {
+	DescribeTable("round-trips recorded account",
+		func(goldenFile string) {
+			golden := readGoldenAccount(goldenFile)
+
+			var account form3apiclient.AccountData
+			Expect(json.Unmarshal(golden, &account)).To(Succeed())
+			encoded, err := json.Marshal(account)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(encoded).To(MatchJSON(golden))
+		},
+		Entry("personal account", "personal_account.json"),
+		Entry("business account", "business_account.json"),
+	)
+
+	It("decodes timestamps, dates and relationships", func() {
+		var account form3apiclient.AccountData
+		Expect(json.Unmarshal(readGoldenAccount("personal_account.json"), &account)).To(Succeed())
+
+		Expect(*account.CreatedOn).To(BeTemporally("==", time.Date(2021, time.April, 26, 13, 54, 47, 521000000, time.UTC)))
+		Expect(*account.Attributes.PrivateIdentification.BirthDate).To(Equal(form3apiclient.NewDate(2017, time.July, 23)))
+		Expect(account.Relationships.MasterAccount.Data).To(Equal([]form3apiclient.ResourceIdentifier{
+			{ID: "a52d13a4-f435-4c00-cfad-f5e7ac5972df", Type: "accounts"},
+		}))
+		Expect(account.Attributes.UserDefinedData).To(Equal([]form3apiclient.UserDefinedData{
+			{Key: "Some account related key", Value: "Some account related value"},
+		}))
+	})
+
+	It("fails to decode malformed date", func() {
+		var identification form3apiclient.PrivateIdentification
+
+		err := json.Unmarshal([]byte(`{"birth_date":"23/07/2017"}`), &identification)
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("does not lose recorded fields when creating account", func() {
+		server := ghttp.NewServer()
+		defer server.Close()
+
+		golden := readGoldenAccount("business_account.json")
+		var account form3apiclient.AccountData
+		Expect(json.Unmarshal(golden, &account)).To(Succeed())
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyJSON(`{"data":`+string(golden)+`}`),
+				ghttp.RespondWith(http.StatusCreated, `{"data":`+string(golden)+`}`)))
+
+		created, err := form3apiclient.MustNew(server.URL()).Accounts().Create(context.Background(), account)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(Equal(account))
+	})
+})
@@ -0,0 +1,89 @@
+package form3apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Bic is a Business Identifier Code (ISO 9362, also known as SWIFT code), e.g. "NWBKGB22" or "NWBKGB2LXXX".
+// It consists of a 4-character institution code, a 2-letter country code, a 2-character location code
+// and an optional 3-character branch code. Use ParseBic to construct valid BICs.
+type Bic string
+
+// bicRegexp matches the institution, country, location and branch codes of a BIC.
+var bicRegexp = regexp.MustCompile(`^([A-Z0-9]{4})([A-Z]{2})([A-Z0-9]{2})([A-Z0-9]{3})?$`)
+
+// primaryOfficeBranchCode is the branch code of the BICs without a branch code (8-character BICs).
+const primaryOfficeBranchCode = "XXX"
+
+// ParseBic validates the given BIC (in the 8 or 11 character form).
+// Surrounding spaces are removed and letters are capitalized.
+// Returns an error wrapping ErrInvalidBic if the BIC is not valid.
+func ParseBic(value string) (Bic, error) {
+	normalized := normalizeBic(value)
+
+	if !bicRegexp.MatchString(normalized) {
+		return "", InvalidBicError(fmt.Sprintf(`"%s" is not a BIC of 8 or 11 characters`, value))
+	}
+
+	return Bic(normalized), nil
+}
+
+// String implements fmt.Stringer.
+func (b Bic) String() string {
+	return string(b)
+}
+
+// InstitutionCode returns the code of the institution (e.g. "NWBK").
+func (b Bic) InstitutionCode() string {
+	return b.part(1)
+}
+
+// CountryCode returns the ISO 3166-1 alpha-2 code of the country of the institution (e.g. "GB").
+func (b Bic) CountryCode() string {
+	return b.part(2)
+}
+
+// LocationCode returns the code of the location of the institution (e.g. "22").
+func (b Bic) LocationCode() string {
+	return b.part(3)
+}
+
+// BranchCode returns the code of the branch (e.g. "123"), "XXX" (the primary office) for 8-character BICs.
+func (b Bic) BranchCode() string {
+	if branchCode := b.part(4); branchCode != "" {
+		return branchCode
+	}
+
+	return primaryOfficeBranchCode
+}
+
+// part returns the given submatch of bicRegexp (empty for invalid BICs).
+func (b Bic) part(index int) string {
+	parts := bicRegexp.FindStringSubmatch(string(b))
+	if parts == nil {
+		return ""
+	}
+
+	return parts[index]
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The BIC is normalised (see ParseBic), but not validated,
+// so that accounts with invalid BICs can still be read (ValidateAccount reports them).
+func (b *Bic) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return WrapError(err, "decoding bic")
+	}
+
+	*b = Bic(normalizeBic(value))
+
+	return nil
+}
+
+// normalizeBic removes surrounding spaces and capitalizes letters of the given BIC.
+func normalizeBic(value string) string {
+	return strings.ToUpper(strings.TrimSpace(value))
+}
@@ -0,0 +1,70 @@
+package form3apiclient_test
+
+import (
+	"encoding/json"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/form3apiclient"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bic", func() {
+	DescribeTable("parses valid BIC",
+		func(value string, expected form3apiclient.Bic, institution, country, location, branch string) {
+			bic, err := form3apiclient.ParseBic(value)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bic).To(Equal(expected))
+			Expect(bic.InstitutionCode()).To(Equal(institution))
+			Expect(bic.CountryCode()).To(Equal(country))
+			Expect(bic.LocationCode()).To(Equal(location))
+			Expect(bic.BranchCode()).To(Equal(branch))
+		},
+		Entry("8 characters", "NWBKGB22", form3apiclient.Bic("NWBKGB22"), "NWBK", "GB", "22", "XXX"),
+		Entry("11 characters", "DEUTDEFF500", form3apiclient.Bic("DEUTDEFF500"), "DEUT", "DE", "FF", "500"),
+		Entry("lower case with spaces", " abnanl2a ", form3apiclient.Bic("ABNANL2A"), "ABNA", "NL", "2A", "XXX"),
+	)
+
+	DescribeTable("rejects invalid BIC",
+		func(value string) {
+			_, err := form3apiclient.ParseBic(value)
+
+			Expect(err).To(MatchError(form3apiclient.ErrInvalidBic))
+		},
+		Entry("empty", ""),
+		Entry("too short", "NWBKGB2"),
+		Entry("9 characters", "NWBKGB22X"),
+		Entry("too long", "DEUTDEFF5001"),
+		Entry("digits in country code", "NWBK1B22"),
+		Entry("inner spaces", "NWBK GB22"),
+	)
+
+	It("decodes normalised BIC", func() {
+		var attributes form3apiclient.AccountAttributes
+
+		err := json.Unmarshal([]byte(`{"bic":"nwbkgb22"}`), &attributes)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attributes.Bic).To(Equal(form3apiclient.Bic("NWBKGB22")))
+	})
+
+	It("decodes empty BIC", func() {
+		var attributes form3apiclient.AccountAttributes
+
+		err := json.Unmarshal([]byte(`{"bic":""}`), &attributes)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attributes.Bic).To(BeEmpty())
+	})
+
+	It("decodes invalid BIC as it is", func() {
+		var account form3apiclient.AccountData
+
+		err := json.Unmarshal([]byte(`{"attributes":{"country":"GB","bic":" nwbk "}}`), &account)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(account.Attributes.Bic).To(Equal(form3apiclient.Bic("NWBK")))
+		Expect(form3apiclient.ValidateAccount(account)).To(ContainElement(
+			form3apiclient.ValidationError{Field: "attributes.bic", Message: "must be a valid BIC of 8 or 11 characters"}))
+	})
+})
@@ -0,0 +1,258 @@
+package form3apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
+)
+
+// defaultExpiryMargin is the default time before token expiry at which the token is refreshed.
+const defaultExpiryMargin = 30 * time.Second
+
+// maxTokenResponseSize is the maximal number of bytes read from a token endpoint response.
+const maxTokenResponseSize = 64 << 10
+
+// defaultTokenTimeout is the default timeout of the token requests.
+const defaultTokenTimeout = 30 * time.Second
+
+// ClientCredentialsConfig represents configuration of a ClientCredentials auth provider.
+type ClientCredentialsConfig struct {
+	// TokenURL is the URL of the OAuth2 token endpoint.
+	TokenURL string
+	// ClientID is the OAuth2 client id.
+	ClientID string
+	// ClientSecret is the OAuth2 client secret.
+	ClientSecret string
+	// Scopes are the optional scopes requested for the token.
+	Scopes []string
+	// HTTPClient is the HTTP client used to call the token endpoint (nil - a default client).
+	HTTPClient *http.Client
+	// ExpiryMargin is the time before the token expiry at which the token is refreshed
+	// (0 - 30 seconds).
+	ExpiryMargin time.Duration
+	// TokenTimeout is the timeout of the token requests (0 - 30 seconds).
+	// Token requests are shared by concurrent API calls, so they are not bound to the context of any of them.
+	TokenTimeout time.Duration
+}
+
+// ClientCredentials is an auth provider (see WithAuthProvider) which authenticates requests
+// with OAuth2 bearer tokens obtained from a token endpoint using the client credentials grant
+// (https://datatracker.ietf.org/doc/html/rfc6749#section-4.4).
+//
+// The token is cached until shortly before its expiry. Concurrent requests which need a new token
+// wait for a single token request. A token rejected by the API is discarded and fetched again.
+//
+// Use NewClientCredentials to construct instances of ClientCredentials.
+type ClientCredentials struct {
+	config ClientCredentialsConfig
+
+	mu    sync.Mutex
+	token *oauth2Token
+	fetch *tokenFetch
+}
+
+type oauth2Token struct {
+	accessToken string
+	// expiry is the time after which the token should be refreshed (zero - never).
+	expiry time.Time
+}
+
+func (t *oauth2Token) isValid() bool {
+	return t != nil && (t.expiry.IsZero() || time.Now().Before(t.expiry))
+}
+
+// tokenFetch represents a token request in progress, which concurrent requests wait for.
+type tokenFetch struct {
+	done  chan struct{}
+	token *oauth2Token
+	err   error
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type tokenErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// NewClientCredentials constructs a ClientCredentials auth provider for the given configuration.
+func NewClientCredentials(config ClientCredentialsConfig) (*ClientCredentials, error) {
+	tokenURL, err := url.Parse(config.TokenURL)
+	if err != nil || !tokenURL.IsAbs() {
+		return nil, URLError("token url must be a valid absolute url")
+	}
+
+	if config.ClientID == "" {
+		return nil, &restresourcehandler.ConfigError{Property: "ClientID", Message: "client id must be set"}
+	}
+
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{}
+	}
+
+	if config.ExpiryMargin == 0 {
+		config.ExpiryMargin = defaultExpiryMargin
+	}
+
+	if config.TokenTimeout == 0 {
+		config.TokenTimeout = defaultTokenTimeout
+	}
+
+	return &ClientCredentials{config: config}, nil
+}
+
+// Authenticate sets the "Authorization" header of the given request to a valid bearer token.
+func (c *ClientCredentials) Authenticate(req *http.Request) error {
+	accessToken, err := c.accessToken(req.Context())
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	return nil
+}
+
+// Invalidate discards the cached token if it is the token the given request has been authenticated with.
+func (c *ClientCredentials) Invalidate(req *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != nil && req.Header.Get("Authorization") == "Bearer "+c.token.accessToken {
+		c.token = nil
+	}
+}
+
+// accessToken returns the cached token or fetches a new one if there is no valid cached token.
+func (c *ClientCredentials) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+
+	if c.token.isValid() {
+		accessToken := c.token.accessToken
+		c.mu.Unlock()
+
+		return accessToken, nil
+	}
+
+	fetch := c.fetch
+	if fetch == nil {
+		fetch = &tokenFetch{done: make(chan struct{})}
+		c.fetch = fetch
+
+		go c.fetchToken(fetch)
+	}
+
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return "", WrapError(ctx.Err(), "waiting for oauth2 token")
+	case <-fetch.done:
+		if fetch.err != nil {
+			return "", fetch.err
+		}
+
+		// the freshly fetched token is used even if it is already due for refresh (short-lived tokens)
+		return fetch.token.accessToken, nil
+	}
+}
+
+// fetchToken requests a new token for all the callers waiting for the given fetch.
+// The request is not bound to the context of any of them, so that a cancelled caller does not fail the others.
+func (c *ClientCredentials) fetchToken(fetch *tokenFetch) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.TokenTimeout)
+	defer cancel()
+
+	token, err := c.requestToken(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fetch = nil
+
+	if err == nil {
+		c.token = token
+	}
+
+	fetch.token = token
+	fetch.err = err
+	close(fetch.done)
+}
+
+func (c *ClientCredentials) requestToken(ctx context.Context) (*oauth2Token, error) {
+	form := url.Values{"grant_type": []string{"client_credentials"}}
+	if len(c.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.config.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, WrapError(err, "constructing oauth2 token request")
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.config.ClientID), url.QueryEscape(c.config.ClientSecret))
+
+	issuedAt := time.Now()
+
+	resp, err := c.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, WrapError(err, "requesting oauth2 token")
+	}
+	defer resp.Body.Close()
+
+	payload, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxTokenResponseSize))
+	if err != nil {
+		return nil, WrapError(err, "reading oauth2 token response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		tokenError := TokenError{StatusCode: resp.StatusCode}
+
+		var errorResponse tokenErrorResponse
+		if err := json.Unmarshal(payload, &errorResponse); err == nil {
+			tokenError.ErrorCode = errorResponse.Error
+			tokenError.Description = errorResponse.ErrorDescription
+		}
+
+		return nil, &tokenError
+	}
+
+	var response tokenResponse
+	if err := json.Unmarshal(payload, &response); err != nil {
+		return nil, WrapError(err, "parsing oauth2 token response json")
+	}
+
+	if response.AccessToken == "" {
+		return nil, AuthenticationError("oauth2 token response contains no access token")
+	}
+
+	if !strings.EqualFold(response.TokenType, "bearer") {
+		return nil, AuthenticationError(fmt.Sprintf(`unsupported oauth2 token type "%s"`, response.TokenType))
+	}
+
+	token := oauth2Token{accessToken: response.AccessToken}
+
+	if response.ExpiresIn > 0 {
+		token.expiry = issuedAt.
+			Add(time.Duration(response.ExpiresIn) * time.Second).
+			Add(-c.config.ExpiryMargin)
+	}
+
+	return &token, nil
+}
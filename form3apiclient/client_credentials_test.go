@@ -0,0 +1,227 @@
+package form3apiclient_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/form3apiclient"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in,omitempty"`
+}
+
+var _ = Describe("ClientCredentials", func() {
+	var tokenServer *ghttp.Server
+	var apiServer *ghttp.Server
+	var tokenRequests int32
+
+	const tokenPath = "/oauth2/token"
+
+	// respondWithTokens issues consecutive tokens ("token-1", "token-2", ...) valid for expiresIn seconds.
+	respondWithTokens := func(expiresIn int64) http.HandlerFunc {
+		return ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", tokenPath),
+			ghttp.VerifyBasicAuth("some-client", "some-secret"),
+			ghttp.VerifyContentType("application/x-www-form-urlencoded"),
+			ghttp.VerifyFormKV("grant_type", "client_credentials"),
+			ghttp.VerifyFormKV("scope", "accounts:read accounts:write"),
+			func(w http.ResponseWriter, req *http.Request) {
+				tokenNumber := atomic.AddInt32(&tokenRequests, 1)
+				time.Sleep(10 * time.Millisecond)
+				ghttp.RespondWithJSONEncoded(
+					http.StatusOK,
+					tokenResponse{fmt.Sprintf("token-%d", tokenNumber), "Bearer", expiresIn})(w, req)
+			})
+	}
+
+	newClient := func(expiryMargin time.Duration) *form3apiclient.Form3ApiClient {
+		authProvider, err := form3apiclient.NewClientCredentials(form3apiclient.ClientCredentialsConfig{
+			TokenURL:     tokenServer.URL() + tokenPath,
+			ClientID:     "some-client",
+			ClientSecret: "some-secret",
+			Scopes:       []string{"accounts:read", "accounts:write"},
+			ExpiryMargin: expiryMargin,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		return form3apiclient.MustNew(apiServer.URL(), form3apiclient.WithAuthProvider(authProvider))
+	}
+
+	BeforeEach(func() {
+		tokenServer = ghttp.NewServer()
+		apiServer = ghttp.NewServer()
+		tokenRequests = 0
+	})
+
+	AfterEach(func() {
+		tokenServer.Close()
+		apiServer.Close()
+	})
+
+	It("caches the token until shortly before expiry", func() {
+		tokenServer.RouteToHandler("POST", tokenPath, respondWithTokens(3600))
+		apiServer.RouteToHandler("DELETE", accountsURL+"/"+someValidUUID, ghttp.CombineHandlers(
+			ghttp.VerifyHeaderKV("Authorization", "Bearer token-1"),
+			ghttp.RespondWith(http.StatusNoContent, nil)))
+		client := newClient(0)
+
+		for i := 0; i < 3; i++ {
+			Expect(client.Accounts().Delete(context.Background(), someValidUUID, 0)).To(Succeed())
+		}
+
+		Expect(tokenServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("refreshes the token when it is about to expire", func() {
+		tokenServer.RouteToHandler("POST", tokenPath, respondWithTokens(60))
+		apiServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("Authorization", "Bearer token-1"),
+				ghttp.RespondWith(http.StatusNoContent, nil)),
+			ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("Authorization", "Bearer token-2"),
+				ghttp.RespondWith(http.StatusNoContent, nil)))
+		client := newClient(time.Minute)
+
+		Expect(client.Accounts().Delete(context.Background(), someValidUUID, 0)).To(Succeed())
+		Expect(client.Accounts().Delete(context.Background(), someValidUUID, 0)).To(Succeed())
+
+		Expect(tokenServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("fetches the token once for concurrent requests", func() {
+		tokenServer.RouteToHandler("POST", tokenPath, respondWithTokens(3600))
+		apiServer.RouteToHandler("DELETE", accountsURL+"/"+someValidUUID, ghttp.CombineHandlers(
+			ghttp.VerifyHeaderKV("Authorization", "Bearer token-1"),
+			ghttp.RespondWith(http.StatusNoContent, nil)))
+		client := newClient(0)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				Expect(client.Accounts().Delete(context.Background(), someValidUUID, 0)).To(Succeed())
+			}()
+		}
+		wg.Wait()
+
+		Expect(tokenServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("does not fail waiting requests when the request which started the fetch is cancelled", func() {
+		tokenServer.RouteToHandler("POST", tokenPath, ghttp.CombineHandlers(
+			func(w http.ResponseWriter, req *http.Request) { time.Sleep(100 * time.Millisecond) },
+			respondWithTokens(3600)))
+		apiServer.RouteToHandler("DELETE", accountsURL+"/"+someValidUUID, ghttp.CombineHandlers(
+			ghttp.VerifyHeaderKV("Authorization", "Bearer token-1"),
+			ghttp.RespondWith(http.StatusNoContent, nil)))
+		client := newClient(0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		cancelledResult := make(chan error)
+		go func() {
+			cancelledResult <- client.Accounts().Delete(ctx, someValidUUID, 0)
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		Expect(client.Accounts().Delete(context.Background(), someValidUUID, 0)).To(Succeed())
+		Expect(<-cancelledResult).To(MatchError(context.DeadlineExceeded))
+		Expect(tokenServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("fails the token request after the token timeout", func() {
+		tokenServer.RouteToHandler("POST", tokenPath, ghttp.CombineHandlers(
+			func(w http.ResponseWriter, req *http.Request) { time.Sleep(100 * time.Millisecond) },
+			respondWithTokens(3600)))
+		authProvider, err := form3apiclient.NewClientCredentials(form3apiclient.ClientCredentialsConfig{
+			TokenURL:     tokenServer.URL() + tokenPath,
+			ClientID:     "some-client",
+			ClientSecret: "some-secret",
+			Scopes:       []string{"accounts:read", "accounts:write"},
+			TokenTimeout: 10 * time.Millisecond,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		client := form3apiclient.MustNew(apiServer.URL(), form3apiclient.WithAuthProvider(authProvider))
+
+		err = client.Accounts().Delete(context.Background(), someValidUUID, 0)
+
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("retries once with a fresh token when the token is rejected", func() {
+		tokenServer.RouteToHandler("POST", tokenPath, respondWithTokens(3600))
+		apiServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("Authorization", "Bearer token-1"),
+				ghttp.RespondWith(http.StatusUnauthorized, nil)),
+			ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("Authorization", "Bearer token-2"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{someValidAccountData(someValidUUID)})))
+		client := newClient(0)
+
+		accountData, err := client.Accounts().Get(context.Background(), someValidUUID)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(accountData).To(Equal(someValidAccountData(someValidUUID)))
+		Expect(tokenServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("reports token endpoint errors", func() {
+		tokenServer.RouteToHandler("POST", tokenPath, ghttp.RespondWithJSONEncoded(
+			http.StatusUnauthorized,
+			map[string]string{"error": "invalid_client", "error_description": "unknown client"}))
+		client := newClient(0)
+
+		_, err := client.Accounts().Get(context.Background(), someValidUUID)
+
+		Expect(err).To(MatchError(form3apiclient.ErrAuthentication))
+		Expect(err).NotTo(MatchError(form3apiclient.ErrRemoteError))
+		Expect(form3apiclient.IsRetryable(err)).To(BeFalse())
+
+		var tokenError *form3apiclient.TokenError
+		Expect(errors.As(err, &tokenError)).To(BeTrue())
+		Expect(*tokenError).To(Equal(form3apiclient.TokenError{
+			StatusCode:  http.StatusUnauthorized,
+			ErrorCode:   "invalid_client",
+			Description: "unknown client",
+		}))
+		Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("reports unsupported token types", func() {
+		tokenServer.RouteToHandler("POST", tokenPath, ghttp.RespondWithJSONEncoded(
+			http.StatusOK,
+			tokenResponse{"some-token", "mac", 3600}))
+		client := newClient(0)
+
+		_, err := client.Accounts().Get(context.Background(), someValidUUID)
+
+		Expect(err).To(MatchError(form3apiclient.ErrAuthentication))
+	})
+
+	It("cannot be constructed with invalid token url", func() {
+		_, err := form3apiclient.NewClientCredentials(form3apiclient.ClientCredentialsConfig{
+			TokenURL: "/oauth2/token",
+			ClientID: "some-client",
+		})
+
+		Expect(err).To(MatchError(form3apiclient.ErrURLError))
+	})
+})
@@ -4,30 +4,139 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
 )
 
 // ErrRemoteError is a static error wrapped by all errors related to
 // the remote server returning an error response.
-var ErrRemoteError = errors.New("remote server returned an error")
+var ErrRemoteError = restresourcehandler.ErrRemoteError
+
+// RemoteError is an error describing an error response of the Form3 API.
+// Apart from the HTTP status it contains the error message and error code sent by the server.
+// RemoteError wraps ErrRemoteError.
+type RemoteError = restresourcehandler.RemoteError
+
+// ErrCircuitOpen is a static error wrapped by all errors related to
+// calls rejected because of an open circuit breaker (see WithCircuitBreaker).
+var ErrCircuitOpen = restresourcehandler.ErrCircuitOpen
+
+// IsNotFound reports whether err is a RemoteError with HTTP status 404 (Not Found).
+func IsNotFound(err error) bool {
+	return restresourcehandler.IsNotFound(err)
+}
+
+// IsConflict reports whether err is a RemoteError with HTTP status 409 (Conflict).
+func IsConflict(err error) bool {
+	return restresourcehandler.IsConflict(err)
+}
+
+// IsRateLimited reports whether err is a RemoteError with HTTP status 429 (Too Many Requests).
+func IsRateLimited(err error) bool {
+	return restresourcehandler.IsRateLimited(err)
+}
+
+// IsRetryable reports whether err is a RemoteError with an HTTP status
+// denoting a transient failure (429 or 5xx), i.e. the request may succeed when retried.
+func IsRetryable(err error) bool {
+	return restresourcehandler.IsRetryable(err)
+}
+
+// ErrVersionConflict is a static error wrapped by all errors related to
+// the server rejecting a change because of a resource version mismatch (optimistic locking).
+var ErrVersionConflict = errors.New("resource version conflict")
+
+type versionConflictError struct {
+	remoteError error
+}
+
+// VersionConflictError decorates a remote error with the information
+// that it was caused by a resource version mismatch.
+// The returned error wraps both ErrVersionConflict and the remote error.
+func VersionConflictError(remoteError error) error {
+	return &versionConflictError{remoteError}
+}
+
+func (e *versionConflictError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrVersionConflict, e.remoteError)
+}
+
+func (e *versionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict //nolint:errorlint,goerr113 // we compare with the sentinel on purpose
+}
+
+func (e *versionConflictError) Unwrap() error {
+	return e.remoteError
+}
+
+// ErrDuplicateConflict is a static error wrapped by all errors related to
+// the server rejecting a created resource because a different resource with the same id already exists.
+var ErrDuplicateConflict = errors.New("resource already exists with different attributes")
+
+type duplicateConflictError struct {
+	remoteError error
+}
+
+// DuplicateConflictError decorates a remote error with the information
+// that it was caused by a different resource with the same id.
+// The returned error wraps both ErrDuplicateConflict and the remote error.
+func DuplicateConflictError(remoteError error) error {
+	return &duplicateConflictError{remoteError}
+}
+
+func (e *duplicateConflictError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrDuplicateConflict, e.remoteError)
+}
+
+func (e *duplicateConflictError) Is(target error) bool {
+	return target == ErrDuplicateConflict //nolint:errorlint,goerr113 // we compare with the sentinel on purpose
+}
+
+func (e *duplicateConflictError) Unwrap() error {
+	return e.remoteError
+}
+
+// ErrAuthentication is a static error wrapped by all errors related to
+// obtaining credentials for the API calls.
+var ErrAuthentication = errors.New("authentication failed")
+
+// AuthenticationError constructs an error for a given error message.
+func AuthenticationError(message string) error {
+	return fmt.Errorf("%w: %s", ErrAuthentication, message)
+}
+
+// TokenError describes an error response of the OAuth2 token endpoint (see ClientCredentials).
+// TokenError wraps ErrAuthentication, i.e. errors.Is(err, ErrAuthentication) holds for it.
+type TokenError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// ErrorCode is the OAuth2 error code sent by the token endpoint (e.g. "invalid_client", empty if none).
+	ErrorCode string
+	// Description is the OAuth2 error description sent by the token endpoint (empty if none).
+	Description string
+}
+
+func (e *TokenError) Error() string {
+	message := fmt.Sprintf(
+		"%s: oauth2 token request failed with http status code \"%d: %s\"",
+		ErrAuthentication,
+		e.StatusCode,
+		http.StatusText(e.StatusCode))
+
+	if e.ErrorCode != "" {
+		message += fmt.Sprintf(", error code: \"%s\"", e.ErrorCode)
+	}
+
+	if e.Description != "" {
+		message += fmt.Sprintf(", description: \"%s\"", e.Description)
+	}
 
-// RemoteError constructs an error for the given HTTP status code.
-func RemoteError(httpStatusCode int) error {
-	return fmt.Errorf(
-		"%w: http status code \"%d: %s\"",
-		ErrRemoteError,
-		httpStatusCode,
-		http.StatusText(httpStatusCode))
+	return message
 }
 
-// RemoteErrorWithServerMessage constructs an error for the given HTTP status code
-// and an additional error message returned by the server.
-func RemoteErrorWithServerMessage(httpStatusCode int, serverMessage string) error {
-	return fmt.Errorf(
-		"%w: http status code \"%d: %s\", server message: \"%s\"",
-		ErrRemoteError,
-		httpStatusCode,
-		http.StatusText(httpStatusCode),
-		serverMessage)
+func (e *TokenError) Is(target error) bool {
+	return target == ErrAuthentication //nolint:errorlint,goerr113 // we compare with the sentinel on purpose
 }
 
 // ErrURLError is a static error wrapped by all errors related to
@@ -39,6 +148,65 @@ func URLError(message string) error {
 	return fmt.Errorf("%w: %s", ErrURLError, message)
 }
 
+// ErrInvalidFilter is a static error wrapped by all errors related to
+// invalid account filter values.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// InvalidFilterError constructs an error for a given error message.
+func InvalidFilterError(message string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidFilter, message)
+}
+
+// ErrUnknownValue is a static error wrapped by all errors related to
+// encoding values unknown to this package (e.g. an unknown Classification).
+var ErrUnknownValue = errors.New("unknown value")
+
+// UnknownValueError constructs an error for a given error message.
+func UnknownValueError(message string) error {
+	return fmt.Errorf("%w: %s", ErrUnknownValue, message)
+}
+
+// ErrInvalidAccount is a static error wrapped by all errors related to
+// accounts failing the local account validation (see WithAccountValidation).
+var ErrInvalidAccount = errors.New("invalid account")
+
+// InvalidAccountError lists the problems found in an account by ValidateAccount.
+// InvalidAccountError wraps ErrInvalidAccount.
+type InvalidAccountError struct {
+	Errors []ValidationError
+}
+
+func (e *InvalidAccountError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, validationError := range e.Errors {
+		messages[i] = validationError.Error()
+	}
+
+	return fmt.Sprintf("%s: %s", ErrInvalidAccount, strings.Join(messages, ", "))
+}
+
+func (e *InvalidAccountError) Is(target error) bool {
+	return target == ErrInvalidAccount //nolint:errorlint,goerr113 // we compare with the sentinel on purpose
+}
+
+// ErrIbanMismatch is a static error wrapped by all errors related to
+// IBANs not matching the other attributes of an account (see AccountData.WithIban).
+var ErrIbanMismatch = errors.New("iban mismatch")
+
+// IbanMismatchError constructs an error for a given error message.
+func IbanMismatchError(message string) error {
+	return fmt.Errorf("%w: %s", ErrIbanMismatch, message)
+}
+
+// ErrInvalidBic is a static error wrapped by all errors related to
+// parsing malformed BICs (see ParseBic).
+var ErrInvalidBic = errors.New("invalid bic")
+
+// InvalidBicError constructs an error for a given error message.
+func InvalidBicError(message string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidBic, message)
+}
+
 // WrapError wraps an external error and decorates it with an additional message.
 func WrapError(err error, message string) error {
 	if err == nil {
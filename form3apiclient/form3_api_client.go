@@ -6,6 +6,7 @@ import (
 
 type form3APIRemoteError struct {
 	ErrorMessage string `json:"error_message"`
+	ErrorCode    string `json:"error_code"`
 }
 
 // Form3ApiClient is a client object used to call the Form3 REST API.
@@ -13,17 +14,48 @@ type Form3ApiClient struct {
 	accountsEndpoint *accounts
 }
 
-// NewForm3APIClient constructs a Form3 API Client for the given URL (e.g. "http://localhost:8080/v1")
-// and HTTP client instance.
+// New constructs a Form3 API Client for the given URL (e.g. "http://localhost:8080/v1")
+// configured with the given options.
 //
-// All HTTP calls will be made using the passed in HTTP client.
-func NewForm3APIClient(apiURL string, httpClient *http.Client) *Form3ApiClient {
-	accounts, err := newAccounts(apiURL, httpClient)
+// Returns an error if the client cannot be constructed (e.g. wrapping ErrURLError if the URL is invalid).
+func New(apiURL string, opts ...Option) (*Form3ApiClient, error) {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var err error
+	if options.httpClient, err = options.httpClientWithSigning(); err != nil {
+		return nil, err
+	}
+
+	accounts, err := newAccounts(apiURL, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Form3ApiClient{accountsEndpoint: accounts}, nil
+}
+
+// MustNew is like New, but panics if the client cannot be constructed.
+func MustNew(apiURL string, opts ...Option) *Form3ApiClient {
+	client, err := New(apiURL, opts...)
 	if err != nil {
 		panic(err)
 	}
 
-	return &Form3ApiClient{accountsEndpoint: accounts}
+	return client
+}
+
+// NewForm3APIClient constructs a Form3 API Client for the given URL (e.g. "http://localhost:8080/v1")
+// and HTTP client instance.
+// Panics if the client cannot be constructed.
+//
+// All HTTP calls will be made using the passed in HTTP client.
+//
+// Deprecated: use New or MustNew with WithHTTPClient option instead.
+func NewForm3APIClient(apiURL string, httpClient *http.Client) *Form3ApiClient {
+	return MustNew(apiURL, WithHTTPClient(httpClient))
 }
 
 // Accounts returns a handler for the accounts endpoint  of the Form3 REST API
@@ -67,7 +67,7 @@ var _ = Describe("Form3ApiClient with real server", Label("e2e"), func() {
 		if apiURL == "" {
 			panic("FORM3_API_URL has to be set")
 		}
-		accounts = form3apiclient.NewForm3APIClient(apiURL, &http.Client{}).Accounts()
+		accounts = form3apiclient.MustNew(apiURL).Accounts()
 
 		DeferCleanup(cleanup)
 	})
@@ -81,7 +81,7 @@ var _ = Describe("Form3ApiClient with real server", Label("e2e"), func() {
 				OrganisationID: uuid.NewString(),
 				Type:           "accounts",
 				Attributes: form3apiclient.AccountAttributes{
-					AccountClassification: "Personal",
+					AccountClassification: form3apiclient.ClassificationPersonal,
 					Name:                  []string{"Jan Kowalski", "Jasiu Kowalski"},
 					Country:               "PL",
 				},
@@ -96,7 +96,7 @@ var _ = Describe("Form3ApiClient with real server", Label("e2e"), func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fetchedAccountData).To(Equal(resource))
 			Expect(fetchedAccountData).To(HaveField("Type", "accounts"))
-			Expect(fetchedAccountData).To(HaveField("Attributes.AccountClassification", "Personal"))
+			Expect(fetchedAccountData).To(HaveField("Attributes.AccountClassification", form3apiclient.ClassificationPersonal))
 			Expect(fetchedAccountData).To(HaveField("Attributes.Country", "PL"))
 			Expect(fetchedAccountData).To(HaveField("Attributes.Name", ConsistOf("Jan Kowalski", "Jasiu Kowalski")))
 		})
@@ -122,7 +122,7 @@ var _ = Describe("Form3ApiClient with real server", Label("e2e"), func() {
 
 			err = accounts.Delete(context.Background(), accountData.ID, accountData.Version)
 			Expect(err).To(HaveOccurred())
-			Expect(err).To(MatchError(form3apiclient.RemoteError(http.StatusNotFound)))
+			Expect(err).To(beRemoteError(http.StatusNotFound, ""))
 		})
 
 		It("when attempting to delete account with invalid version", func() {
@@ -136,8 +136,7 @@ var _ = Describe("Form3ApiClient with real server", Label("e2e"), func() {
 
 			err = accounts.Delete(context.Background(), accountData.ID, accountData.Version+1)
 			Expect(err).To(HaveOccurred())
-			Expect(err).To(MatchError(
-				form3apiclient.RemoteErrorWithServerMessage(http.StatusConflict, "invalid version")))
+			Expect(err).To(beRemoteError(http.StatusConflict, "invalid version"))
 		})
 
 		It("when creating account with invalid data", func() {
@@ -147,13 +146,12 @@ var _ = Describe("Form3ApiClient with real server", Label("e2e"), func() {
 			_, err := createAndScheduleCleanup(invalidAccountData)
 
 			Expect(err).To(HaveOccurred())
-			Expect(err).To(MatchError(
-				form3apiclient.RemoteErrorWithServerMessage(
-					http.StatusBadRequest,
-					"validation failure list:\nvalidation failure list:\nvalidation failure list:\nname in body is required")))
+			Expect(err).To(beRemoteError(
+				http.StatusBadRequest,
+				"validation failure list:\nvalidation failure list:\nvalidation failure list:\nname in body is required"))
 		})
 
-		It("creating a duplicate account", func() {
+		It("creating the same account again", func() {
 			var err error
 			var accountData form3apiclient.AccountData
 
@@ -162,12 +160,29 @@ var _ = Describe("Form3ApiClient with real server", Label("e2e"), func() {
 				Expect(err).NotTo(HaveOccurred())
 			})
 
+			existingData, err := accounts.Create(context.Background(), someValidAccountData(accountData.ID))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(existingData).To(Equal(accountData))
+		})
+
+		It("creating a duplicate account with different attributes", func() {
+			var err error
+			var accountData form3apiclient.AccountData
+
+			By("making sure the account does exist", func() {
+				accountData, err = createAndScheduleCleanup(someValidAccountData(uuid.NewString()))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			accountData.Attributes.Name = []string{"Jan Nowak"}
 			_, err = createAndScheduleCleanup(accountData)
 
 			Expect(err).To(HaveOccurred())
-			Expect(err).To(MatchError(form3apiclient.RemoteErrorWithServerMessage(
+			Expect(err).To(MatchError(form3apiclient.ErrDuplicateConflict))
+			Expect(err).To(beRemoteError(
 				http.StatusConflict,
-				"Account cannot be created as it violates a duplicate constraint")))
+				"Account cannot be created as it violates a duplicate constraint"))
 		})
 
 		It("fetching a non-existent account", func() {
@@ -185,10 +200,7 @@ var _ = Describe("Form3ApiClient with real server", Label("e2e"), func() {
 			_, err = accounts.Get(context.Background(), accountData.ID)
 
 			Expect(err).To(HaveOccurred())
-			Expect(err).To(MatchError(
-				form3apiclient.RemoteErrorWithServerMessage(
-					http.StatusNotFound,
-					"record "+accountData.ID+" does not exist")))
+			Expect(err).To(beRemoteError(http.StatusNotFound, "record "+accountData.ID+" does not exist"))
 		})
 	})
 })
@@ -2,10 +2,16 @@ package form3apiclient_test
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/jannis-baratheon/form3-take-home-exercise/form3apiclient"
+	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
@@ -21,8 +27,42 @@ type wrapper struct {
 	AccountData form3apiclient.AccountData `json:"data"`
 }
 
+type pageLinks struct {
+	Next string `json:"next,omitempty"`
+}
+
+type pageWrapper struct {
+	AccountData []form3apiclient.AccountData `json:"data"`
+	Links       pageLinks                    `json:"links"`
+}
+
 type remoteError struct {
 	Message string `json:"error_message"`
+	Code    string `json:"error_code,omitempty"`
+}
+
+// countingLogger is a restresourcehandler.Logger which counts logged entries.
+type countingLogger struct {
+	count int
+}
+
+func (l *countingLogger) Debug(string, ...interface{}) { l.count++ }
+func (l *countingLogger) Info(string, ...interface{})  { l.count++ }
+func (l *countingLogger) Warn(string, ...interface{})  { l.count++ }
+func (l *countingLogger) Error(string, ...interface{}) { l.count++ }
+
+// printingLogger is a restresourcehandler.Logger which prints all logged entries.
+type printingLogger struct {
+	output string
+}
+
+func (l *printingLogger) Debug(msg string, keyvals ...interface{}) { l.print(msg, keyvals) }
+func (l *printingLogger) Info(msg string, keyvals ...interface{})  { l.print(msg, keyvals) }
+func (l *printingLogger) Warn(msg string, keyvals ...interface{})  { l.print(msg, keyvals) }
+func (l *printingLogger) Error(msg string, keyvals ...interface{}) { l.print(msg, keyvals) }
+
+func (l *printingLogger) print(msg string, keyvals []interface{}) {
+	l.output += fmt.Sprintln(append([]interface{}{msg}, keyvals...)...)
 }
 
 type apiCall func(client *form3apiclient.Form3ApiClient) error
@@ -42,6 +82,18 @@ func getExampleValidAPICalls() map[string]apiCall {
 
 			return err //nolint:wrapcheck // we need this error unwrapped
 		},
+		"accounts update": func(client *form3apiclient.Form3ApiClient) error {
+			_, err := client.Accounts().Update(context.Background(), someValidUUID, 0, form3apiclient.AccountChanges{})
+
+			return err //nolint:wrapcheck // we need this error unwrapped
+		},
+		"accounts list": func(client *form3apiclient.Form3ApiClient) error {
+			it := client.Accounts().List(context.Background(), form3apiclient.ListOptions{})
+			for it.Next() {
+			}
+
+			return it.Err() //nolint:wrapcheck // we need this error unwrapped
+		},
 	}
 }
 
@@ -59,13 +111,34 @@ var _ = Describe("Form3ApiClient", func() {
 
 	BeforeEach(func() {
 		server = ghttp.NewServer()
-		client = form3apiclient.NewForm3APIClient(server.URL(), &http.Client{})
+		client = form3apiclient.MustNew(server.URL(), form3apiclient.WithHTTPClient(&http.Client{}))
 	})
 
 	AfterEach(func() {
 		server.Close()
 	})
 
+	Context("on construction", func() {
+		It("returns client for valid url", func() {
+			client, err := form3apiclient.New(server.URL())
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client).NotTo(BeNil())
+		})
+
+		It("returns error for invalid url", func() {
+			client, err := form3apiclient.New("example.com/v1")
+
+			Expect(err).To(MatchError(form3apiclient.ErrURLError))
+			Expect(client).To(BeNil())
+		})
+
+		It("panics in MustNew for invalid url", func() {
+			Expect(func() { form3apiclient.MustNew("example.com/v1") }).
+				To(PanicWith(MatchError(form3apiclient.ErrURLError)))
+		})
+	})
+
 	Context("on happy-path", func() {
 		It("gets account", func() {
 			expectedData := someValidAccountData(someValidUUID)
@@ -82,6 +155,25 @@ var _ = Describe("Form3ApiClient", func() {
 			Expect(response).To(Equal(expectedData))
 		})
 
+		It("gets account along with response metadata", func() {
+			expectedData := someValidAccountData(someValidUUID)
+
+			server.AppendHandlers(
+				ghttp.RespondWith(
+					http.StatusOK,
+					`{"data":{"id":"`+someValidUUID+`"},"links":{"self":"/v1/organisation/accounts"},"meta":{"foo":"bar"}}`,
+					http.Header{"X-Request-Id": []string{"some-request-id"}}))
+
+			response, err := client.Accounts().GetResponse(context.Background(), expectedData.ID)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Data.ID).To(Equal(someValidUUID))
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(response.RequestID).To(Equal("some-request-id"))
+			Expect(response.Links.Self).To(Equal("/v1/organisation/accounts"))
+			Expect(string(response.Meta)).To(Equal(`{"foo":"bar"}`))
+		})
+
 		It("deletes account", func() {
 			accountID := someValidUUID
 
@@ -112,6 +204,200 @@ var _ = Describe("Form3ApiClient", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actualResponse).To(Equal(expectedData))
 		})
+
+		It("updates account", func() {
+			newStatus := form3apiclient.AccountStatusClosed
+			expectedData := someValidAccountData(someValidUUID)
+			expectedData.Version = 4
+			expectedData.Attributes.Status = newStatus
+			expectedData.Attributes.Name = []string{"Jan Nowak"}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PATCH", accountsURL+"/"+someValidUUID),
+					ghttp.VerifyContentType(resourceEncoding),
+					ghttp.VerifyHeaderKV("Accept", resourceEncoding),
+					ghttp.VerifyJSON(`{
+						"data": {
+							"id": "`+someValidUUID+`",
+							"type": "accounts",
+							"version": 3,
+							"attributes": {
+								"name": ["Jan Nowak"],
+								"status": "closed",
+								"switched": false
+							}
+						}
+					}`),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{expectedData})))
+
+			switched := false
+			name := []string{"Jan Nowak"}
+			actualResponse, err := client.Accounts().Update(
+				context.Background(),
+				someValidUUID,
+				3,
+				form3apiclient.AccountChanges{
+					Name:     &name,
+					Status:   &newStatus,
+					Switched: &switched,
+				})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualResponse).To(Equal(expectedData))
+		})
+
+		It("clears alternative names of account", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PATCH", accountsURL+"/"+someValidUUID),
+					ghttp.VerifyJSON(`{
+						"data": {
+							"id": "`+someValidUUID+`",
+							"type": "accounts",
+							"version": 3,
+							"attributes": {
+								"alternative_names": []
+							}
+						}
+					}`),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{someValidAccountData(someValidUUID)})))
+
+			_, err := client.Accounts().Update(
+				context.Background(),
+				someValidUUID,
+				3,
+				form3apiclient.AccountChanges{AlternativeNames: &[]string{}})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("lists accounts following next page links", func() {
+			firstPage := []form3apiclient.AccountData{
+				someValidAccountData("50a5a8f4-7e4b-11ec-90d6-0242ac120003"),
+				someValidAccountData("50a5ab9c-7e4b-11ec-90d6-0242ac120003"),
+			}
+			secondPage := []form3apiclient.AccountData{
+				someValidAccountData("50a5acb4-7e4b-11ec-90d6-0242ac120003"),
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", accountsURL, "page[number]=0&page[size]=2"),
+					ghttp.VerifyHeaderKV("Accept", resourceEncoding),
+					ghttp.RespondWithJSONEncoded(
+						http.StatusOK,
+						pageWrapper{firstPage, pageLinks{"/v1" + accountsURL + "?page%5Bnumber%5D=1&page%5Bsize%5D=2"}})),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", accountsURL, "page[number]=1&page[size]=2"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, pageWrapper{secondPage, pageLinks{}})))
+
+			it := client.Accounts().List(context.Background(), form3apiclient.ListOptions{PageSize: 2})
+
+			var actualAccounts []form3apiclient.AccountData
+			for it.Next() {
+				actualAccounts = append(actualAccounts, it.Account())
+			}
+
+			Expect(it.Err()).NotTo(HaveOccurred())
+			Expect(actualAccounts).To(Equal(append(firstPage, secondPage...)))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("provides metadata of the fetched account pages", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(
+					http.StatusOK,
+					pageWrapper{[]form3apiclient.AccountData{someValidAccountData(someValidUUID)}, pageLinks{}},
+					http.Header{"X-Request-Id": []string{"some-request-id"}}))
+
+			it := client.Accounts().List(context.Background(), form3apiclient.ListOptions{})
+
+			Expect(it.Next()).To(BeTrue())
+			Expect(it.PageMetadata().RequestID).To(Equal("some-request-id"))
+		})
+
+		It("fetches account pages lazily", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", accountsURL, "page[number]=3&page[size]=1"),
+					ghttp.RespondWithJSONEncoded(
+						http.StatusOK,
+						pageWrapper{
+							[]form3apiclient.AccountData{someValidAccountData(someValidUUID)},
+							pageLinks{accountsURL + "?page%5Bnumber%5D=4&page%5Bsize%5D=1"},
+						})))
+
+			it := client.Accounts().List(context.Background(), form3apiclient.ListOptions{PageNumber: 3, PageSize: 1})
+
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+			Expect(it.Next()).To(BeTrue())
+			Expect(it.Account()).To(Equal(someValidAccountData(someValidUUID)))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("lists accounts using filter", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest(
+						"GET",
+						accountsURL,
+						"filter[country]=FR,GB&filter[bank_id]=400300&page[number]=0&page[size]=1"),
+					ghttp.RespondWithJSONEncoded(
+						http.StatusOK,
+						pageWrapper{
+							[]form3apiclient.AccountData{someValidAccountData(someValidUUID)},
+							pageLinks{accountsURL + "?page%5Bnumber%5D=1&page%5Bsize%5D=1"},
+						})),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest(
+						"GET",
+						accountsURL,
+						"filter[country]=FR,GB&filter[bank_id]=400300&page[number]=1&page[size]=1"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, pageWrapper{nil, pageLinks{}})))
+
+			it := client.Accounts().List(
+				context.Background(),
+				form3apiclient.ListOptions{
+					PageSize: 1,
+					Filter:   form3apiclient.NewAccountFilter().Country("GB", "FR").BankID("400300"),
+				})
+
+			Expect(it.Next()).To(BeTrue())
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.Err()).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("does not list accounts using invalid filter", func() {
+			it := client.Accounts().List(
+				context.Background(),
+				form3apiclient.ListOptions{Filter: form3apiclient.NewAccountFilter().Country("Great Britain")})
+
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.Err()).To(MatchError(form3apiclient.ErrInvalidFilter))
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("stops listing accounts when context is cancelled", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.RespondWithJSONEncoded(
+						http.StatusOK,
+						pageWrapper{
+							[]form3apiclient.AccountData{someValidAccountData(someValidUUID)},
+							pageLinks{accountsURL + "?page%5Bnumber%5D=1"},
+						})))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			it := client.Accounts().List(ctx, form3apiclient.ListOptions{})
+
+			Expect(it.Next()).To(BeTrue())
+			cancel()
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.Err()).To(MatchError(context.Canceled))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
 	})
 
 	Context("when remote error occurs", func() {
@@ -122,7 +408,9 @@ var _ = Describe("Form3ApiClient", func() {
 			BeforeEach(func() {
 				server.AppendHandlers(
 					ghttp.CombineHandlers(
-						ghttp.RespondWithJSONEncoded(expectedErrorStatus, remoteError{expectedRemoteErrorMessage})))
+						ghttp.RespondWithJSONEncoded(
+							expectedErrorStatus,
+							remoteError{expectedRemoteErrorMessage, "some-error-code"})))
 			})
 
 			forEachExampleValidAPICall(func(callName string, call apiCall) {
@@ -132,6 +420,165 @@ var _ = Describe("Form3ApiClient", func() {
 					Expect(err).To(HaveOccurred())
 					Expect(err.Error()).To(ContainSubstring(expectedRemoteErrorMessage))
 				})
+
+				It(fmt.Sprintf(`returns structured remote error for "%s" call`, callName), func() {
+					err := call(client)
+
+					var remoteError *form3apiclient.RemoteError
+					Expect(errors.As(err, &remoteError)).To(BeTrue())
+					Expect(remoteError.StatusCode).To(Equal(expectedErrorStatus))
+					Expect(remoteError.ServerMessage).To(Equal(expectedRemoteErrorMessage))
+					Expect(remoteError.ErrorCode).To(Equal("some-error-code"))
+					Expect(remoteError.URL).To(HavePrefix(server.URL() + accountsURL))
+				})
+			})
+		})
+
+		Context("and server responds with a non-JSON error", func() {
+			It("keeps an excerpt of the response as server message", func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusBadGateway, "<html>bad gateway</html>"))
+
+				_, err := client.Accounts().Get(context.Background(), someValidUUID)
+
+				Expect(err).To(beRemoteError(http.StatusBadGateway, "<html>bad gateway</html>"))
+			})
+
+			It("reads at most the excerpt length of the response", func() {
+				body := strings.Repeat("x", 10*restresourcehandler.MaxRemoteErrorBodyLength)
+				server.AppendHandlers(ghttp.RespondWith(http.StatusBadGateway, body))
+
+				_, err := client.Accounts().Get(context.Background(), someValidUUID)
+
+				var remoteError *form3apiclient.RemoteError
+				Expect(errors.As(err, &remoteError)).To(BeTrue())
+				Expect(remoteError.ServerMessage).To(Equal(body[:restresourcehandler.MaxRemoteErrorBodyLength]))
+			})
+		})
+
+		Context("and server reports a conflict", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.RespondWithJSONEncoded(http.StatusConflict, remoteError{Message: "invalid version"})))
+			})
+
+			It(`reports conflict for "accounts delete" call`, func() {
+				err := client.Accounts().Delete(context.Background(), someValidUUID, 1)
+
+				Expect(err).To(beRemoteError(http.StatusConflict, "invalid version"))
+				Expect(form3apiclient.IsConflict(err)).To(BeTrue())
+			})
+
+			It(`reports version conflict for "accounts update" call`, func() {
+				_, err := client.Accounts().Update(context.Background(), someValidUUID, 1, form3apiclient.AccountChanges{})
+
+				Expect(err).To(MatchError(form3apiclient.ErrVersionConflict))
+				Expect(err).To(MatchError(form3apiclient.ErrRemoteError))
+				Expect(err.Error()).To(ContainSubstring("invalid version"))
+			})
+
+			It(`does not report version conflict for "accounts create" call`, func() {
+				// the conflict is not caused by the account id
+				server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, nil))
+
+				_, err := client.Accounts().Create(context.Background(), someValidAccountData(someValidUUID))
+
+				Expect(err).To(MatchError(form3apiclient.ErrRemoteError))
+				Expect(err).NotTo(MatchError(form3apiclient.ErrVersionConflict))
+			})
+		})
+
+		Context("and server reports a conflict when creating an account", func() {
+			var requestedData form3apiclient.AccountData
+
+			BeforeEach(func() {
+				requestedData = someValidAccountData(someValidUUID)
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", accountsURL),
+						ghttp.RespondWithJSONEncoded(http.StatusConflict, remoteError{Message: "duplicate id"})))
+			})
+
+			It("returns the existing account if it has the same attributes", func() {
+				existingData := someValidAccountData(someValidUUID)
+				existingData.Attributes.Status = form3apiclient.AccountStatusConfirmed
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", accountsURL+"/"+someValidUUID),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{existingData})))
+
+				actualData, err := client.Accounts().Create(context.Background(), requestedData)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualData).To(Equal(existingData))
+			})
+
+			It("reports duplicate conflict if the existing account has different attributes", func() {
+				existingData := someValidAccountData(someValidUUID)
+				existingData.Attributes.Name = []string{"Jan Nowak"}
+
+				server.AppendHandlers(
+					ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{existingData}))
+
+				_, err := client.Accounts().Create(context.Background(), requestedData)
+
+				Expect(err).To(MatchError(form3apiclient.ErrDuplicateConflict))
+				Expect(err).To(beRemoteError(http.StatusConflict, "duplicate id"))
+			})
+
+			It("does not compare attributes with zero values, as they are not sent", func() {
+				requestedData.Attributes.JointAccount = false
+				existingData := someValidAccountData(someValidUUID)
+				existingData.Attributes.JointAccount = true
+
+				server.AppendHandlers(
+					ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{existingData}))
+
+				actualData, err := client.Accounts().Create(context.Background(), requestedData)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualData).To(Equal(existingData))
+			})
+
+			It("does not compare unknown values of the existing account which are not sent", func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusOK, `{"data":{
+						"id":"`+someValidUUID+`",
+						"organisation_id":"`+someValidUUID+`",
+						"type":"accounts",
+						"attributes":{"account_classification":"Personal","name":["Jan Kowalski"],"country":"PL","status":"frozen"}
+					}}`))
+
+				actualData, err := client.Accounts().Create(context.Background(), requestedData)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualData.Attributes.Status).To(Equal(form3apiclient.AccountStatus("frozen")))
+			})
+
+			It("reports duplicate conflict if the existing account has unknown value instead of the requested one", func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusOK, `{"data":{
+						"id":"`+someValidUUID+`",
+						"organisation_id":"`+someValidUUID+`",
+						"type":"accounts",
+						"attributes":{"account_classification":"Charity","name":["Jan Kowalski"],"country":"PL"}
+					}}`))
+
+				_, err := client.Accounts().Create(context.Background(), requestedData)
+
+				Expect(err).To(MatchError(form3apiclient.ErrDuplicateConflict))
+			})
+
+			It("reports error if the existing account cannot be fetched", func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, nil))
+
+				_, err := client.Accounts().Create(context.Background(), requestedData)
+
+				Expect(err).To(MatchError(form3apiclient.ErrRemoteError))
+				Expect(err).NotTo(MatchError(form3apiclient.ErrDuplicateConflict))
+				Expect(err.Error()).To(ContainSubstring("fetching conflicting account"))
 			})
 		})
 
@@ -154,4 +601,330 @@ var _ = Describe("Form3ApiClient", func() {
 			})
 		})
 	})
+
+	Context("with options", func() {
+		It("sends user agent and headers", func() {
+			client = form3apiclient.MustNew(
+				server.URL(),
+				form3apiclient.WithUserAgent("some-agent/1.0"),
+				form3apiclient.WithHeader("X-Some-Header", "some value"))
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("User-Agent", "some-agent/1.0"),
+					ghttp.VerifyHeaderKV("X-Some-Header", "some value"),
+					ghttp.RespondWith(http.StatusNoContent, nil)))
+
+			err := client.Accounts().Delete(context.Background(), someValidUUID, 0)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("creates account using default organisation id", func() {
+			const defaultOrganisationID = "4bfa9ac5-0e4c-4b0a-98da-a0f4b3bb1e07"
+			client = form3apiclient.MustNew(server.URL(), form3apiclient.WithDefaultOrganisationID(defaultOrganisationID))
+			requestData := someValidAccountData(someValidUUID)
+			requestData.OrganisationID = ""
+			expectedRequestData := requestData
+			expectedRequestData.OrganisationID = defaultOrganisationID
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyJSONRepresenting(wrapper{expectedRequestData}),
+					ghttp.RespondWithJSONEncoded(http.StatusCreated, wrapper{expectedRequestData})))
+
+			_, err := client.Accounts().Create(context.Background(), requestData)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not override organisation id with the default one", func() {
+			client = form3apiclient.MustNew(server.URL(), form3apiclient.WithDefaultOrganisationID(someOtherValidUUID))
+			requestData := someValidAccountData(someValidUUID)
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyJSONRepresenting(wrapper{requestData}),
+					ghttp.RespondWithJSONEncoded(http.StatusCreated, wrapper{requestData})))
+
+			_, err := client.Accounts().Create(context.Background(), requestData)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("retries calls according to retry policy", func() {
+			client = form3apiclient.MustNew(
+				server.URL(),
+				form3apiclient.WithRetryPolicy(restresourcehandler.RetryPolicy{MaxAttempts: 2}))
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{someValidAccountData(someValidUUID)}))
+
+			_, err := client.Accounts().Get(context.Background(), someValidUUID)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("retries account creation using account id as idempotency key", func() {
+			client = form3apiclient.MustNew(
+				server.URL(),
+				form3apiclient.WithRetryPolicy(restresourcehandler.RetryPolicy{MaxAttempts: 2}))
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("Idempotency-Key", someValidUUID),
+					ghttp.RespondWith(http.StatusServiceUnavailable, nil)),
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("Idempotency-Key", someValidUUID),
+					ghttp.RespondWithJSONEncoded(http.StatusCreated, wrapper{someValidAccountData(someValidUUID)})))
+
+			_, err := client.Accounts().Create(context.Background(), someValidAccountData(someValidUUID))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("retries account creation using random idempotency key if account has no id", func() {
+			client = form3apiclient.MustNew(
+				server.URL(),
+				form3apiclient.WithRetryPolicy(restresourcehandler.RetryPolicy{MaxAttempts: 2}))
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+				ghttp.RespondWithJSONEncoded(http.StatusCreated, wrapper{someValidAccountData(someValidUUID)}))
+
+			_, err := client.Accounts().Create(context.Background(), someValidAccountData(""))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+
+			key := server.ReceivedRequests()[0].Header.Get("Idempotency-Key")
+			Expect(key).NotTo(BeEmpty())
+			Expect(server.ReceivedRequests()[1].Header.Get("Idempotency-Key")).To(Equal(key))
+		})
+
+		It("does not send idempotency keys if disabled", func() {
+			client = form3apiclient.MustNew(server.URL(), form3apiclient.WithIdempotencyKey(nil))
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeader(http.Header{"Idempotency-Key": nil}),
+					ghttp.RespondWithJSONEncoded(http.StatusCreated, wrapper{someValidAccountData(someValidUUID)})))
+
+			_, err := client.Accounts().Create(context.Background(), someValidAccountData(someValidUUID))
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("limits request rate", func() {
+			limiter, err := restresourcehandler.NewRateLimiter(1000, 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			client = form3apiclient.MustNew(server.URL(), form3apiclient.WithRateLimiter(limiter))
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusTooManyRequests, nil, http.Header{"Retry-After": []string{"60"}}))
+
+			_, err = client.Accounts().Get(context.Background(), someValidUUID)
+
+			Expect(form3apiclient.IsRateLimited(err)).To(BeTrue())
+			Expect(limiter.State().BlockedUntil).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+		})
+
+		It("fails fast when circuit breaker is open", func() {
+			var transitions []string
+			client = form3apiclient.MustNew(
+				server.URL(),
+				form3apiclient.WithCircuitBreaker(restresourcehandler.CircuitBreakerConfig{
+					FailureRatio:   1,
+					MinRequests:    1,
+					Window:         time.Minute,
+					CoolDown:       time.Minute,
+					HalfOpenProbes: 1,
+					OnStateChange: func(name string, from, to restresourcehandler.CircuitState) {
+						transitions = append(transitions, fmt.Sprintf("%s: %s -> %s", name, from, to))
+					},
+				}))
+			server.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, nil))
+
+			_, err := client.Accounts().Get(context.Background(), someValidUUID)
+			Expect(err).To(beRemoteError(http.StatusInternalServerError, ""))
+
+			_, err = client.Accounts().Get(context.Background(), someValidUUID)
+			Expect(err).To(MatchError(form3apiclient.ErrCircuitOpen))
+
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+			Expect(transitions).To(Equal([]string{"organisation/accounts: closed -> open"}))
+		})
+
+		It("records metrics of calls", func() {
+			metrics := restresourcehandler.NewInMemoryMetrics()
+			client = form3apiclient.MustNew(server.URL(), form3apiclient.WithMetrics(metrics))
+			server.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, nil))
+
+			_, err := client.Accounts().Get(context.Background(), someValidUUID)
+
+			Expect(err).To(HaveOccurred())
+			Expect(metrics.Snapshot()).To(HaveLen(1))
+			Expect(metrics.Snapshot()[0].Labels).To(Equal(restresourcehandler.MetricLabels{
+				Resource:    "accounts",
+				Method:      http.MethodGet,
+				StatusClass: "5xx",
+				ErrorKind:   restresourcehandler.ErrorKindRemote,
+			}))
+		})
+
+		It("traces calls", func() {
+			tracer := restresourcehandler.NewRecordingTracer()
+			client = form3apiclient.MustNew(server.URL(), form3apiclient.WithTracer(tracer))
+			server.AppendHandlers(ghttp.RespondWith(http.StatusNoContent, nil))
+
+			err := client.Accounts().Delete(context.Background(), someValidUUID, 0)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tracer.Spans()).To(HaveLen(2))
+			Expect(tracer.Spans()[1].Name).To(Equal("accounts DELETE"))
+			Expect(server.ReceivedRequests()[0].Header.Get("traceparent")).
+				To(Equal(tracer.Spans()[0].TraceContext.Traceparent()))
+		})
+
+		It("fails calls exceeding timeout", func() {
+			client = form3apiclient.MustNew(server.URL(), form3apiclient.WithTimeout(20*time.Millisecond))
+			server.AppendHandlers(
+				func(w http.ResponseWriter, r *http.Request) {
+					time.Sleep(200 * time.Millisecond)
+				})
+
+			_, err := client.Accounts().Get(context.Background(), someValidUUID)
+
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+		})
+
+		It("fails calls with responses exceeding maximal response size", func() {
+			client = form3apiclient.MustNew(server.URL(), form3apiclient.WithMaxResponseSize(64))
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{someValidAccountData(someValidUUID)}))
+
+			_, err := client.Accounts().Get(context.Background(), someValidUUID)
+
+			Expect(err).To(MatchError(restresourcehandler.ErrResponseTooLarge))
+		})
+
+		It("logs calls", func() {
+			logger := &countingLogger{}
+			client = form3apiclient.MustNew(server.URL(), form3apiclient.WithLogger(logger))
+			server.AppendHandlers(ghttp.RespondWith(http.StatusNoContent, nil))
+
+			err := client.Accounts().Delete(context.Background(), someValidUUID, 0)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.count).To(BeNumerically(">", 0))
+		})
+
+		It("masks sensitive account data in logs", func() {
+			logger := &printingLogger{}
+			client = form3apiclient.MustNew(
+				server.URL(), form3apiclient.WithLogger(logger), form3apiclient.WithBodyLogging())
+			account := someValidAccountData(someValidUUID)
+			account.Attributes.Iban = "GB11NWBK40030041426819"
+			account.Attributes.AccountNumber = "41426819"
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pageWrapper{AccountData: []form3apiclient.AccountData{account}}))
+
+			it := client.Accounts().List(context.Background(), form3apiclient.ListOptions{
+				Filter: form3apiclient.NewAccountFilter().Iban("GB11NWBK40030041426819").AccountNumber("41426819"),
+			})
+			Expect(it.Next()).To(BeTrue())
+
+			Expect(it.Err()).NotTo(HaveOccurred())
+			Expect(logger.output).To(ContainSubstring(`"iban":"******************6819"`))
+			Expect(logger.output).To(ContainSubstring(`"account_number":"****6819"`))
+			Expect(logger.output).To(ContainSubstring(`"name":["********lski"]`))
+			Expect(logger.output).NotTo(ContainSubstring("GB11NWBK40030041426819"))
+			Expect(logger.output).NotTo(ContainSubstring("41426819"))
+			Expect(logger.output).NotTo(ContainSubstring("Kowalski"))
+		})
+
+		It("intercepts calls", func() {
+			var operations []restresourcehandler.Operation
+			client = form3apiclient.MustNew(
+				server.URL(),
+				form3apiclient.WithInterceptors(func(
+					ctx context.Context,
+					op *restresourcehandler.Operation,
+					next restresourcehandler.Invoker) error {
+					operations = append(operations, *op)
+					op.Header.Set("X-Operation", op.Method+" "+op.ResourceID)
+
+					return next(ctx, op)
+				}))
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("X-Operation", "DELETE "+someValidUUID),
+					ghttp.RespondWith(http.StatusNoContent, nil)))
+
+			err := client.Accounts().Delete(context.Background(), someValidUUID, 0)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(operations).To(HaveLen(1))
+			Expect(operations[0].QueryParams).To(Equal(map[string]string{"version": "0"}))
+		})
+
+		It("signs requests", func() {
+			privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).NotTo(HaveOccurred())
+			client = form3apiclient.MustNew(
+				server.URL(),
+				form3apiclient.WithRequestSigning(someValidUUID, privateKey))
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					func(w http.ResponseWriter, req *http.Request) {
+						keyID, err := restresourcehandler.VerifyRequestSignature(req, &privateKey.PublicKey)
+
+						Expect(err).NotTo(HaveOccurred())
+						Expect(keyID).To(Equal(someValidUUID))
+					},
+					ghttp.RespondWithJSONEncoded(http.StatusCreated, wrapper{someValidAccountData(someValidUUID)})))
+
+			_, err = client.Accounts().Create(context.Background(), someValidAccountData(someValidUUID))
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns error for invalid signing key id", func() {
+			privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = form3apiclient.New(server.URL(), form3apiclient.WithRequestSigning("", privateKey))
+
+			Expect(err).To(MatchError(restresourcehandler.ErrInvalidConfig))
+		})
+
+		It("returns error for request signing combined with auth provider", func() {
+			privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+			Expect(err).NotTo(HaveOccurred())
+			authProvider, err := form3apiclient.NewClientCredentials(form3apiclient.ClientCredentialsConfig{
+				TokenURL: server.URL() + "/oauth2/token",
+				ClientID: "some-client",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = form3apiclient.New(
+				server.URL(),
+				form3apiclient.WithRequestSigning(someValidUUID, privateKey),
+				form3apiclient.WithAuthProvider(authProvider))
+
+			Expect(err).To(MatchError(restresourcehandler.ErrInvalidConfig))
+		})
+
+		It("returns error for invalid circuit breaker configuration", func() {
+			_, err := form3apiclient.New(
+				server.URL(),
+				form3apiclient.WithCircuitBreaker(restresourcehandler.CircuitBreakerConfig{}))
+
+			Expect(err).To(MatchError(restresourcehandler.ErrInvalidConfig))
+		})
+
+		It("returns error for invalid options", func() {
+			_, err := form3apiclient.New(server.URL(), form3apiclient.WithTimeout(-time.Second))
+
+			Expect(err).To(MatchError(restresourcehandler.ErrInvalidConfig))
+		})
+	})
 })
@@ -0,0 +1,170 @@
+package iban
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Account are the account details an IBAN is built from (see Build).
+type Account struct {
+	// Country is the ISO 3166-1 alpha-2 country code of the account (e.g. "GB").
+	Country string
+	// BankID is the national bank id of the account (e.g. the sort code "400300").
+	BankID string
+	// AccountNumber is the national account number (e.g. "41426819").
+	AccountNumber string
+	// Bic is the BIC of the bank, required in the countries which use its bank code in their BBANs (GB and NL).
+	Bic string
+}
+
+// bbanBuilders compose the BBANs of the countries supported by Build.
+var bbanBuilders = map[string]func(account Account) (string, error){
+	"BE": func(account Account) (string, error) {
+		return withNationalCheckDigits(account.BankID+account.AccountNumber, belgianCheckDigits)
+	},
+	"CH": func(account Account) (string, error) {
+		return account.BankID + leftPad(account.AccountNumber, 12), nil
+	},
+	"DE": func(account Account) (string, error) {
+		return account.BankID + leftPad(account.AccountNumber, 10), nil
+	},
+	"ES": func(account Account) (string, error) {
+		return account.BankID + spanishCheckDigits(account.BankID, account.AccountNumber) + account.AccountNumber, nil
+	},
+	"GB": func(account Account) (string, error) {
+		bankCode, err := bicBankCode(account)
+
+		return bankCode + account.BankID + account.AccountNumber, err
+	},
+	"GR": func(account Account) (string, error) {
+		return account.BankID + account.AccountNumber, nil
+	},
+	"LU": func(account Account) (string, error) {
+		return account.BankID + account.AccountNumber, nil
+	},
+	"NL": func(account Account) (string, error) {
+		bankCode, err := bicBankCode(account)
+
+		return bankCode + leftPad(account.AccountNumber, 10), err
+	},
+	"PL": func(account Account) (string, error) {
+		return account.BankID + account.AccountNumber, nil
+	},
+	"PT": func(account Account) (string, error) {
+		return withNationalCheckDigits(account.BankID+account.AccountNumber, portugueseCheckDigits)
+	},
+}
+
+// Build builds the IBAN of the given account (the check digits are computed).
+// Supported countries: BE, CH, DE, ES, GB, GR, LU, NL, PL and PT.
+// Returns an error wrapping ErrCannotBuild if the country is not supported
+// or the account details do not form a valid BBAN.
+func Build(account Account) (IBAN, error) {
+	builder, ok := bbanBuilders[account.Country]
+	if !ok {
+		return "", CannotBuildError(fmt.Sprintf(`building IBANs is not supported for country "%s"`, account.Country))
+	}
+
+	if account.BankID != "" && !isDigits(account.BankID) {
+		return "", CannotBuildError("bank id must consist of digits")
+	}
+
+	bban, err := builder(account)
+	if err != nil {
+		return "", err
+	}
+
+	bban = strings.ToUpper(bban)
+
+	if spec := specs[account.Country]; len(bban) != spec.length || !spec.structure.MatchString(bban) {
+		return "", CannotBuildError(fmt.Sprintf("bank id and account number do not form a valid %s BBAN", account.Country))
+	}
+
+	return IBAN(account.Country + computeCheckDigits(account.Country, bban) + bban), nil
+}
+
+// bicBankCode returns the bank code of the BIC of the account (its first 4 letters).
+func bicBankCode(account Account) (string, error) {
+	const bankCodeLength = 4
+
+	if len(account.Bic) < bankCodeLength {
+		return "", CannotBuildError(fmt.Sprintf("building %s IBANs requires a BIC", account.Country))
+	}
+
+	return account.Bic[:bankCodeLength], nil
+}
+
+func withNationalCheckDigits(bban string, checkDigits func(digits string) string) (string, error) {
+	if !isDigits(bban) {
+		return "", CannotBuildError("bank id and account number must consist of digits")
+	}
+
+	return bban + checkDigits(bban), nil
+}
+
+// belgianCheckDigits computes the check digits of a Belgian account number (mod 97, 97 instead of 0).
+func belgianCheckDigits(digits string) string {
+	remainder := mod97(digits)
+	if remainder == 0 {
+		remainder = checksumModulus
+	}
+
+	return fmt.Sprintf("%02d", remainder)
+}
+
+// portugueseCheckDigits computes the check digits of a Portuguese NIB (ISO 7064 mod 97-10).
+func portugueseCheckDigits(digits string) string {
+	return fmt.Sprintf("%02d", checksumModulus+1-mod97(digits+"00"))
+}
+
+// spanishCheckDigits computes the two control digits of a Spanish CCC:
+// the first one for the bank and branch codes, the second one for the account number.
+func spanishCheckDigits(bankID string, accountNumber string) string {
+	if !isDigits(accountNumber) {
+		// the BBAN is invalid anyway
+		return "00"
+	}
+
+	return spanishControlDigit("00"+bankID) + spanishControlDigit(accountNumber)
+}
+
+func spanishControlDigit(digits string) string {
+	weights := []int{1, 2, 4, 8, 5, 10, 9, 7, 3, 6}
+
+	sum := 0
+	for i, char := range digits {
+		if i < len(weights) {
+			sum += int(char-'0') * weights[i]
+		}
+	}
+
+	const modulus = 11
+
+	switch digit := modulus - sum%modulus; digit {
+	case modulus:
+		return "0"
+	case modulus - 1:
+		return "1"
+	default:
+		return strconv.Itoa(digit)
+	}
+}
+
+func leftPad(value string, length int) string {
+	if len(value) >= length {
+		return value
+	}
+
+	return strings.Repeat("0", length-len(value)) + value
+}
+
+func isDigits(value string) bool {
+	for _, char := range value {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+
+	return value != ""
+}
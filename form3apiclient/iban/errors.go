@@ -0,0 +1,24 @@
+package iban
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidIBAN is a static error wrapped by all errors related to
+// parsing malformed IBANs (see Parse).
+var ErrInvalidIBAN = errors.New("invalid iban")
+
+// InvalidIBANError constructs an error for a given error message.
+func InvalidIBANError(message string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidIBAN, message)
+}
+
+// ErrCannotBuild is a static error wrapped by all errors related to
+// building IBANs from account details (see Build).
+var ErrCannotBuild = errors.New("cannot build iban")
+
+// CannotBuildError constructs an error for a given error message.
+func CannotBuildError(message string) error {
+	return fmt.Errorf("%w: %s", ErrCannotBuild, message)
+}
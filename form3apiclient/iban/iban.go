@@ -0,0 +1,182 @@
+// Package iban parses, validates and builds International Bank Account Numbers (ISO 13616).
+//
+//	parsed, err := iban.Parse("gb11 nwbk 4003 0041 4268 19")
+//	parsed.String() // "GB11NWBK40030041426819"
+//	parsed.Format() // "GB11 NWBK 4003 0041 4268 19"
+package iban
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// IBAN is a valid IBAN in the electronic format (upper case, without spaces, e.g. "GB11NWBK40030041426819").
+// Use Parse or Build to construct valid IBANs.
+type IBAN string
+
+const (
+	// countryCodeLength is the length of the country code at the start of an IBAN.
+	countryCodeLength = 2
+	// bbanOffset is the offset of the BBAN in an IBAN, i.e. the length of the country code and the check digits.
+	bbanOffset = 4
+)
+
+// String returns the IBAN in the electronic format (e.g. "GB11NWBK40030041426819").
+func (i IBAN) String() string {
+	return string(i)
+}
+
+// Format returns the IBAN in the print format, i.e. in groups of four characters (e.g. "GB11 NWBK 4003 0041 4268 19").
+func (i IBAN) Format() string {
+	const groupLength = 4
+
+	var groups []string
+	for value := string(i); value != ""; {
+		length := groupLength
+		if len(value) < length {
+			length = len(value)
+		}
+
+		groups = append(groups, value[:length])
+		value = value[length:]
+	}
+
+	return strings.Join(groups, " ")
+}
+
+// CountryCode returns the ISO 3166-1 alpha-2 country code of the IBAN (e.g. "GB").
+// Returns an empty string if the IBAN is too short to contain it (e.g. the zero value).
+func (i IBAN) CountryCode() string {
+	if len(i) < countryCodeLength {
+		return ""
+	}
+
+	return string(i[:countryCodeLength])
+}
+
+// CheckDigits returns the check digits of the IBAN (e.g. "11").
+// Returns an empty string if the IBAN is too short to contain them (e.g. the zero value).
+func (i IBAN) CheckDigits() string {
+	if len(i) < bbanOffset {
+		return ""
+	}
+
+	return string(i[countryCodeLength:bbanOffset])
+}
+
+// BBAN returns the national part of the IBAN (e.g. "NWBK40030041426819").
+// Returns an empty string if the IBAN is too short to contain it (e.g. the zero value).
+func (i IBAN) BBAN() string {
+	if len(i) < bbanOffset {
+		return ""
+	}
+
+	return string(i[bbanOffset:])
+}
+
+// Parse validates the given IBAN and returns it in the electronic format.
+// Spaces are removed and letters are capitalized (i.e. both the electronic and the print format are accepted).
+// The IBAN is checked against the length and the BBAN structure of its country and against its check digits (mod-97).
+// Returns an error wrapping ErrInvalidIBAN if the IBAN is not valid.
+// The errors do not contain the IBAN itself, so that they can be logged.
+func Parse(value string) (IBAN, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(value, " ", ""))
+
+	if len(normalized) < bbanOffset || !countryCodeRegexp.MatchString(normalized[:countryCodeLength]) {
+		return "", InvalidIBANError("missing country code")
+	}
+
+	country, checkDigits, bban :=
+		normalized[:countryCodeLength], normalized[countryCodeLength:bbanOffset], normalized[bbanOffset:]
+
+	spec, ok := specs[country]
+	if !ok {
+		return "", InvalidIBANError(fmt.Sprintf(`country "%s" does not use IBANs`, country))
+	}
+
+	switch {
+	case len(bban) != spec.length:
+		return "", InvalidIBANError(fmt.Sprintf("%s IBANs must have %d characters", country, spec.length+4))
+	case !spec.structure.MatchString(bban):
+		return "", InvalidIBANError(fmt.Sprintf("invalid %s BBAN structure", country))
+	case checkDigits != computeCheckDigits(country, bban):
+		return "", InvalidIBANError("invalid check digits")
+	}
+
+	return IBAN(normalized), nil
+}
+
+// Validate reports whether the given IBAN is valid (see Parse).
+func Validate(value string) error {
+	_, err := Parse(value)
+
+	return err
+}
+
+// IsSupported reports whether the given country uses IBANs.
+func IsSupported(countryCode string) bool {
+	_, ok := specs[countryCode]
+
+	return ok
+}
+
+// spec is the BBAN specification of a country.
+type spec struct {
+	length    int
+	structure *regexp.Regexp
+}
+
+var (
+	countryCodeRegexp = regexp.MustCompile(`^[A-Z]{2}$`)
+	bbanPartRegexp    = regexp.MustCompile(`([0-9]+)!([nac])`)
+	specs             = compileSpecs(bbanFormats)
+)
+
+var bbanCharacterClasses = map[string]string{"n": "[0-9]", "a": "[A-Z]", "c": "[A-Z0-9]"}
+
+func compileSpecs(formats map[string]string) map[string]spec {
+	compiled := make(map[string]spec, len(formats))
+
+	for country, format := range formats {
+		var s spec
+
+		pattern := "^"
+
+		for _, part := range bbanPartRegexp.FindAllStringSubmatch(format, -1) {
+			length, _ := strconv.Atoi(part[1])
+			s.length += length
+			pattern += fmt.Sprintf("%s{%d}", bbanCharacterClasses[part[2]], length)
+		}
+
+		s.structure = regexp.MustCompile(pattern + "$")
+		compiled[country] = s
+	}
+
+	return compiled
+}
+
+// checksumModulus is the modulus of the IBAN checksum (ISO 7064 mod 97-10).
+const checksumModulus = 97
+
+// computeCheckDigits computes the IBAN check digits of the given BBAN (ISO 7064 mod 97-10).
+func computeCheckDigits(country string, bban string) string {
+	return fmt.Sprintf("%02d", checksumModulus+1-mod97(bban+country+"00"))
+}
+
+// mod97 computes the remainder of the division by 97 of the number represented by the given
+// alphanumeric string (letters stand for 10-35).
+func mod97(value string) int {
+	remainder := 0
+
+	for _, char := range value {
+		if char >= '0' && char <= '9' {
+			remainder = (remainder*10 + int(char-'0')) % checksumModulus
+		} else {
+			remainder = (remainder*100 + int(char-'A') + 10) % checksumModulus //nolint:gomnd // letters are two digits
+		}
+	}
+
+	return remainder
+}
@@ -0,0 +1,13 @@
+package iban_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestIbanModule(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "iban testsuite")
+}
@@ -0,0 +1,157 @@
+package iban_test
+
+import (
+	"github.com/jannis-baratheon/form3-take-home-exercise/form3apiclient/iban"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IBAN", func() {
+	DescribeTable("parses valid IBAN",
+		func(value string, expected iban.IBAN) {
+			parsed, err := iban.Parse(value)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(expected))
+			Expect(iban.Validate(value)).To(Succeed())
+		},
+		Entry("GB", "GB82WEST12345698765432", iban.IBAN("GB82WEST12345698765432")),
+		Entry("print format", "GB82 WEST 1234 5698 7654 32", iban.IBAN("GB82WEST12345698765432")),
+		Entry("lower case", "gb82west12345698765432", iban.IBAN("GB82WEST12345698765432")),
+		Entry("BE", "BE68539007547034", iban.IBAN("BE68539007547034")),
+		Entry("CH", "CH9300762011623852957", iban.IBAN("CH9300762011623852957")),
+		Entry("DE", "DE89370400440532013000", iban.IBAN("DE89370400440532013000")),
+		Entry("ES", "ES9121000418450200051332", iban.IBAN("ES9121000418450200051332")),
+		Entry("FR", "FR1420041010050500013M02606", iban.IBAN("FR1420041010050500013M02606")),
+		Entry("GR", "GR1601101250000000012300695", iban.IBAN("GR1601101250000000012300695")),
+		Entry("IT", "IT60X0542811101000000123456", iban.IBAN("IT60X0542811101000000123456")),
+		Entry("LU", "LU280019400644750000", iban.IBAN("LU280019400644750000")),
+		Entry("NL", "NL91ABNA0417164300", iban.IBAN("NL91ABNA0417164300")),
+		Entry("PL", "PL61109010140000071219812874", iban.IBAN("PL61109010140000071219812874")),
+		Entry("PT", "PT50000201231234567890154", iban.IBAN("PT50000201231234567890154")),
+	)
+
+	DescribeTable("validates IBAN registry examples",
+		func(value string) {
+			Expect(iban.Validate(value)).To(Succeed())
+		},
+		Entry("BI", "BI4210000100010000332045181"),
+		Entry("BY", "BY13NBRB3600900000002Z00AB00"),
+		Entry("DJ", "DJ2100010000000154000100186"),
+		Entry("EG", "EG380019000500000000263180002"),
+		Entry("FK", "FK88SC123456789012"),
+		Entry("IQ", "IQ98NBIQ850123456789012"),
+		Entry("LC", "LC55HEMM000100010012001200023015"),
+		Entry("LY", "LY83002048000020100120361"),
+		Entry("MN", "MN121234123456789123"),
+		Entry("NI", "NI45BAPR00000013000003558124"),
+		Entry("OM", "OM810180000001299123456"),
+		Entry("RU", "RU0204452560040702810412345678901"),
+		Entry("SC", "SC18SSCB11010000000000001497USD"),
+		Entry("SD", "SD2129010501234001"),
+		Entry("SO", "SO211000001001000100141"),
+		Entry("ST", "ST68000100010051845310112"),
+		Entry("SV", "SV62CENR00000000000000700025"),
+		Entry("TL", "TL380080012345678910157"),
+		Entry("UA", "UA213223130000026007233566001"),
+		Entry("VA", "VA59001123000012345678"),
+		Entry("XK", "XK051212012345678906"),
+		Entry("YE", "YE15CBYE0001018861234567891234"),
+	)
+
+	DescribeTable("rejects invalid IBAN",
+		func(value string, expectedMessage string) {
+			_, err := iban.Parse(value)
+
+			Expect(err).To(MatchError(iban.ErrInvalidIBAN))
+			Expect(err).To(MatchError("invalid iban: " + expectedMessage))
+			Expect(iban.Validate(value)).To(MatchError(iban.ErrInvalidIBAN))
+		},
+		Entry("empty", "", "missing country code"),
+		Entry("missing country code", "8212345698765432", "missing country code"),
+		Entry("unknown country", "XX82WEST12345698765432", `country "XX" does not use IBANs`),
+		Entry("too short", "GB82WEST1234569876543", "GB IBANs must have 22 characters"),
+		Entry("too long", "GB82WEST123456987654321", "GB IBANs must have 22 characters"),
+		Entry("invalid BBAN structure", "GB82WES912345698765432", "invalid GB BBAN structure"),
+		Entry("invalid check digits", "GB83WEST12345698765432", "invalid check digits"),
+		Entry("swapped digits", "GB82WEST12345698765423", "invalid check digits"),
+	)
+
+	It("formats IBAN in groups of four", func() {
+		Expect(iban.IBAN("GB82WEST12345698765432").Format()).To(Equal("GB82 WEST 1234 5698 7654 32"))
+		Expect(iban.IBAN("BE68539007547034").Format()).To(Equal("BE68 5390 0754 7034"))
+	})
+
+	It("splits IBAN into its parts", func() {
+		parsed := iban.IBAN("GB82WEST12345698765432")
+
+		Expect(parsed.CountryCode()).To(Equal("GB"))
+		Expect(parsed.CheckDigits()).To(Equal("82"))
+		Expect(parsed.BBAN()).To(Equal("WEST12345698765432"))
+		Expect(parsed.String()).To(Equal("GB82WEST12345698765432"))
+	})
+
+	DescribeTable("returns empty parts of too short IBAN",
+		func(value iban.IBAN, expectedCountryCode string) {
+			Expect(value.CountryCode()).To(Equal(expectedCountryCode))
+			Expect(value.CheckDigits()).To(BeEmpty())
+			Expect(value.BBAN()).To(BeEmpty())
+		},
+		Entry("zero value", iban.IBAN(""), ""),
+		Entry("single character", iban.IBAN("G"), ""),
+		Entry("partial check digits", iban.IBAN("GB8"), "GB"),
+	)
+
+	It("knows countries using IBANs", func() {
+		Expect(iban.IsSupported("GB")).To(BeTrue())
+		Expect(iban.IsSupported("US")).To(BeFalse())
+	})
+
+	DescribeTable("builds IBAN",
+		func(account iban.Account, expected iban.IBAN) {
+			built, err := iban.Build(account)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(built).To(Equal(expected))
+		},
+		Entry("BE", iban.Account{Country: "BE", BankID: "539", AccountNumber: "0075470"},
+			iban.IBAN("BE68539007547034")),
+		Entry("CH", iban.Account{Country: "CH", BankID: "00762", AccountNumber: "011623852957"},
+			iban.IBAN("CH9300762011623852957")),
+		Entry("DE", iban.Account{Country: "DE", BankID: "37040044", AccountNumber: "532013000"},
+			iban.IBAN("DE89370400440532013000")),
+		Entry("ES", iban.Account{Country: "ES", BankID: "21000418", AccountNumber: "0200051332"},
+			iban.IBAN("ES9121000418450200051332")),
+		Entry("GB", iban.Account{Country: "GB", BankID: "123456", AccountNumber: "98765432", Bic: "WESTGB22"},
+			iban.IBAN("GB82WEST12345698765432")),
+		Entry("GR", iban.Account{Country: "GR", BankID: "0110125", AccountNumber: "0000000012300695"},
+			iban.IBAN("GR1601101250000000012300695")),
+		Entry("LU", iban.Account{Country: "LU", BankID: "001", AccountNumber: "9400644750000"},
+			iban.IBAN("LU280019400644750000")),
+		Entry("NL", iban.Account{Country: "NL", AccountNumber: "417164300", Bic: "ABNANL2A"},
+			iban.IBAN("NL91ABNA0417164300")),
+		Entry("PL", iban.Account{Country: "PL", BankID: "10901014", AccountNumber: "0000071219812874"},
+			iban.IBAN("PL61109010140000071219812874")),
+		Entry("PT", iban.Account{Country: "PT", BankID: "00020123", AccountNumber: "12345678901"},
+			iban.IBAN("PT50000201231234567890154")),
+	)
+
+	DescribeTable("refuses to build IBAN",
+		func(account iban.Account, expectedMessage string) {
+			_, err := iban.Build(account)
+
+			Expect(err).To(MatchError(iban.ErrCannotBuild))
+			Expect(err).To(MatchError("cannot build iban: " + expectedMessage))
+		},
+		Entry("unsupported country", iban.Account{Country: "FR", BankID: "2004101005", AccountNumber: "0500013M026"},
+			`building IBANs is not supported for country "FR"`),
+		Entry("missing BIC", iban.Account{Country: "GB", BankID: "123456", AccountNumber: "98765432"},
+			"building GB IBANs requires a BIC"),
+		Entry("too short account number", iban.Account{Country: "GB", BankID: "123456", AccountNumber: "9876543", Bic: "WESTGB22"},
+			"bank id and account number do not form a valid GB BBAN"),
+		Entry("non-numeric bank id", iban.Account{Country: "DE", BankID: "3704004X", AccountNumber: "532013000"},
+			"bank id must consist of digits"),
+		Entry("non-numeric account number", iban.Account{Country: "BE", BankID: "539", AccountNumber: "007547X"},
+			"bank id and account number must consist of digits"),
+	)
+})
@@ -0,0 +1,223 @@
+package form3apiclient
+
+import (
+	"crypto"
+	"net/http"
+	"time"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
+)
+
+// Option configures a Form3ApiClient (see New).
+type Option func(*options)
+
+// options represents the configuration of a Form3ApiClient.
+type options struct {
+	httpClient            *http.Client
+	header                http.Header
+	defaultOrganisationID string
+	timeout               time.Duration
+	maxResponseSize       int64
+	retryPolicy           *restresourcehandler.RetryPolicy
+	rateLimiter           *restresourcehandler.RateLimiter
+	circuitBreaker        *restresourcehandler.CircuitBreakerConfig
+	logger                restresourcehandler.Logger
+	logLevel              restresourcehandler.LogLevel
+	logBodies             bool
+	metrics               restresourcehandler.Metrics
+	tracer                restresourcehandler.Tracer
+	authProvider          restresourcehandler.AuthProvider
+	interceptors          []restresourcehandler.Interceptor
+	idempotencyKey        restresourcehandler.IdempotencyKeyFunc
+	isAccountValidated    bool
+	signingKeyID          string
+	signingKey            crypto.Signer
+}
+
+func defaultOptions() options {
+	return options{
+		httpClient:     &http.Client{},
+		header:         http.Header{},
+		idempotencyKey: accountIdempotencyKey,
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for all calls made by a Form3ApiClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with all requests.
+func WithUserAgent(userAgent string) Option {
+	return WithHeader("User-Agent", userAgent)
+}
+
+// WithHeader sets a header sent with all requests.
+func WithHeader(key string, value string) Option {
+	return func(o *options) {
+		o.header.Set(key, value)
+	}
+}
+
+// WithDefaultOrganisationID sets the organisation id used when creating resources
+// which have no organisation id set.
+func WithDefaultOrganisationID(organisationID string) Option {
+	return func(o *options) {
+		o.defaultOrganisationID = organisationID
+	}
+}
+
+// WithTimeout limits the duration of every API call (including all retries of the call).
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithMaxResponseSize limits the size of API responses (in bytes).
+// Reading a larger response fails with an error wrapping restresourcehandler.ErrResponseTooLarge.
+// Error responses are not limited, as only an excerpt of them is read.
+func WithMaxResponseSize(maxResponseSize int64) Option {
+	return func(o *options) {
+		o.maxResponseSize = maxResponseSize
+	}
+}
+
+// WithRetryPolicy makes the client retry failed idempotent API calls according to the given policy.
+func WithRetryPolicy(policy restresourcehandler.RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = &policy
+	}
+}
+
+// WithRateLimiter limits the rate of requests sent by the client using the given limiter
+// (see restresourcehandler.RateLimiter). The limiter is shared by all resources of the client
+// and can also be shared by multiple clients.
+func WithRateLimiter(limiter *restresourcehandler.RateLimiter) Option {
+	return func(o *options) {
+		o.rateLimiter = limiter
+	}
+}
+
+// WithCircuitBreaker makes the client use a separate circuit breaker for every resource endpoint
+// (see restresourcehandler.CircuitBreaker). Calls to an endpoint with an open circuit fail fast
+// with an error wrapping ErrCircuitOpen. The resource path (e.g. "organisation/accounts")
+// is used as the name of the circuit unless config.Name is set.
+func WithCircuitBreaker(config restresourcehandler.CircuitBreakerConfig) Option {
+	return func(o *options) {
+		o.circuitBreaker = &config
+	}
+}
+
+// WithAccountValidation makes the client validate the created accounts against the local account rules
+// of their country before sending them (see ValidateAccount). Invalid accounts are not sent,
+// the calls fail with an InvalidAccountError listing the problems instead.
+func WithAccountValidation() Option {
+	return func(o *options) {
+		o.isAccountValidated = true
+	}
+}
+
+// WithLogger makes the client log the sent requests and received responses using the given logger.
+// The values of SensitiveFields are masked in the logged URLs and bodies.
+func WithLogger(logger restresourcehandler.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithLogLevel sets the minimal level of the messages logged by the client (default LogLevelDebug, see WithLogger).
+func WithLogLevel(level restresourcehandler.LogLevel) Option {
+	return func(o *options) {
+		o.logLevel = level
+	}
+}
+
+// WithBodyLogging makes the client log the request and response bodies at debug level (see WithLogger).
+// The values of SensitiveFields (e.g. "iban") keep only their last 4 characters in the logged bodies.
+func WithBodyLogging() Option {
+	return func(o *options) {
+		o.logBodies = true
+	}
+}
+
+// WithMetrics makes the client report the count and the latency of all API calls to the given metrics
+// (e.g. restresourcehandler.InMemoryMetrics). The calls are labelled with the resource name (e.g. "accounts"),
+// the HTTP method, the HTTP status class and the error kind (see restresourcehandler.MetricLabels).
+func WithMetrics(metrics restresourcehandler.Metrics) Option {
+	return func(o *options) {
+		o.metrics = metrics
+	}
+}
+
+// WithTracer makes the client trace all API calls using the given tracer (see restresourcehandler.Tracer).
+// The trace context is propagated to the API in the W3C "traceparent" and "tracestate" headers.
+func WithTracer(tracer restresourcehandler.Tracer) Option {
+	return func(o *options) {
+		o.tracer = tracer
+	}
+}
+
+// WithAuthProvider makes the client authenticate all requests using the given auth provider
+// (e.g. ClientCredentials).
+// It cannot be combined with WithRequestSigning, as both set the Authorization header.
+func WithAuthProvider(authProvider restresourcehandler.AuthProvider) Option {
+	return func(o *options) {
+		o.authProvider = authProvider
+	}
+}
+
+// WithInterceptors adds interceptors of all API calls made by the client (see restresourcehandler.Interceptor).
+// Interceptors are called in the order they have been added.
+func WithInterceptors(interceptors ...restresourcehandler.Interceptor) Option {
+	return func(o *options) {
+		o.interceptors = append(o.interceptors, interceptors...)
+	}
+}
+
+// WithIdempotencyKey sets the generator of the idempotency keys sent with create calls
+// (nil - no idempotency keys are sent, see restresourcehandler.Config.IdempotencyKey).
+// By default the id of the created account is used as the key (a random key is generated if the id is not set).
+func WithIdempotencyKey(idempotencyKey restresourcehandler.IdempotencyKeyFunc) Option {
+	return func(o *options) {
+		o.idempotencyKey = idempotencyKey
+	}
+}
+
+// WithRequestSigning makes the client sign all requests with the given private key
+// (*rsa.PrivateKey or *ecdsa.PrivateKey) according to the HTTP Signatures scheme required by the Form3 API.
+// keyID is the id of the public key registered in Form3.
+// Request signing cannot be combined with WithAuthProvider, as both set the Authorization header.
+func WithRequestSigning(keyID string, privateKey crypto.Signer) Option {
+	return func(o *options) {
+		o.signingKeyID = keyID
+		o.signingKey = privateKey
+	}
+}
+
+// httpClientWithSigning returns the HTTP client to be used for the API calls,
+// wrapping the transport of the configured client with a signing transport if request signing is enabled.
+func (o options) httpClientWithSigning() (*http.Client, error) {
+	if o.signingKey == nil || o.httpClient == nil {
+		return o.httpClient, nil
+	}
+
+	if o.authProvider != nil {
+		return nil, &restresourcehandler.ConfigError{
+			Property: "AuthProvider",
+			Message:  "auth provider cannot be used together with request signing",
+		}
+	}
+
+	signer, err := restresourcehandler.NewRequestSigner(o.signingKeyID, o.signingKey)
+	if err != nil {
+		return nil, WrapError(err, "configuring request signing")
+	}
+
+	httpClient := *o.httpClient
+	httpClient.Transport = &restresourcehandler.SigningTransport{Signer: signer, Base: o.httpClient.Transport}
+
+	return &httpClient, nil
+}
@@ -0,0 +1,11 @@
+package form3apiclient
+
+import "github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
+
+// ResponseMetadata describes an API response apart from the returned resource:
+// the HTTP status, the response headers, the request id ("X-Request-Id", needed when escalating issues to Form3),
+// the rate limit state, the links and meta sent along the resource and the latency of the call.
+type ResponseMetadata = restresourcehandler.ResponseMetadata
+
+// AccountResponse is account data along with the metadata of the API response.
+type AccountResponse = restresourcehandler.Response[AccountData]
@@ -2,40 +2,74 @@ package form3apiclient
 
 import (
 	"encoding/json"
+	"io"
 	"io/ioutil"
 	"net/http"
 
 	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
 )
 
+// SensitiveFields are the fields masked in the logged URLs and bodies (see WithLogger and WithBodyLogging).
+var SensitiveFields = []string{
+	"account_number",
+	"iban",
+	"name",
+	"alternative_names",
+	"secondary_identification",
+	"private_identification",
+	"birth_date",
+}
+
 // getRestResourceHandlerConfig constructs a configuration object for all
 // Rest Resource Handlers used in this package.
-func getRestResourceHandlerConfig() restresourcehandler.Config {
+func getRestResourceHandlerConfig(options options) restresourcehandler.Config {
 	return restresourcehandler.Config{
 		ResourceEncoding:     "application/json; charset=utf-8",
 		IsDataWrapped:        true,
 		DataPropertyName:     "data",
+		LinksPropertyName:    "links",
+		MetaPropertyName:     "meta",
 		RemoteErrorExtractor: extractRemoteError,
+		RetryPolicy:          options.retryPolicy,
+		RateLimiter:          options.rateLimiter,
+		Header:               options.header.Clone(),
+		Timeout:              options.timeout,
+		MaxResponseSize:      options.maxResponseSize,
+		Logger:               options.logger,
+		LogLevel:             options.logLevel,
+		LogBodies:            options.logBodies,
+		Redactor:             restresourcehandler.FieldRedactor{Fields: SensitiveFields},
+		Metrics:              options.metrics,
+		Tracer:               options.tracer,
+		AuthProvider:         options.authProvider,
+		Interceptors:         options.interceptors,
+		IdempotencyKey:       options.idempotencyKey,
 	}
 }
 
 // extractRemoteError extracts additional information from the JSON sent along an error response.
+// If the response is not a valid error JSON, an excerpt of it is used as the server message.
 func extractRemoteError(response *http.Response) error {
-	if response.ContentLength == 0 {
-		return RemoteError(response.StatusCode)
-	}
-
-	respPayload, err := ioutil.ReadAll(response.Body)
+	respPayload, err := ioutil.ReadAll(io.LimitReader(response.Body, restresourcehandler.MaxRemoteErrorBodyLength))
 	if err != nil {
 		return WrapError(err, "reading response")
 	}
 
-	var remoteError form3APIRemoteError
-	err = json.Unmarshal(respPayload, &remoteError)
+	remoteError := restresourcehandler.NewRemoteError(response, respPayload)
 
-	if err != nil {
-		return WrapError(err, "parsing response json")
+	if len(respPayload) == 0 {
+		return remoteError
 	}
 
-	return RemoteErrorWithServerMessage(response.StatusCode, remoteError.ErrorMessage)
+	var serverError form3APIRemoteError
+	if err := json.Unmarshal(respPayload, &serverError); err != nil {
+		remoteError.ServerMessage = remoteError.Body
+
+		return remoteError
+	}
+
+	remoteError.ServerMessage = serverError.ErrorMessage
+	remoteError.ErrorCode = serverError.ErrorCode
+
+	return remoteError
 }
@@ -1,6 +1,12 @@
 package form3apiclient_test
 
-import "github.com/jannis-baratheon/form3-take-home-exercise/form3apiclient"
+import (
+	"errors"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/form3apiclient"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+)
 
 func someValidAccountData(id string) form3apiclient.AccountData {
 	return form3apiclient.AccountData{
@@ -8,9 +14,27 @@ func someValidAccountData(id string) form3apiclient.AccountData {
 		OrganisationID: someValidUUID,
 		Type:           "accounts",
 		Attributes: form3apiclient.AccountAttributes{
-			AccountClassification: "Personal",
+			AccountClassification: form3apiclient.ClassificationPersonal,
 			Name:                  []string{"Jan Kowalski"},
 			Country:               "PL",
 		},
 	}
 }
+
+// beRemoteError matches a RemoteError with the given HTTP status code and server message.
+func beRemoteError(statusCode int, serverMessage string) types.GomegaMatcher {
+	return SatisfyAll(
+		MatchError(form3apiclient.ErrRemoteError),
+		WithTransform(
+			func(err error) form3apiclient.RemoteError {
+				var remoteError *form3apiclient.RemoteError
+				if !errors.As(err, &remoteError) {
+					return form3apiclient.RemoteError{}
+				}
+
+				return *remoteError
+			},
+			SatisfyAll(
+				HaveField("StatusCode", statusCode),
+				HaveField("ServerMessage", serverMessage))))
+}
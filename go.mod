@@ -1,6 +1,6 @@
 module github.com/jannis-baratheon/form3-take-home-exercise
 
-go 1.17
+go 1.18
 
 require (
 	github.com/google/uuid v1.3.0
@@ -0,0 +1,14 @@
+package restresourcehandler
+
+import "net/http"
+
+// AuthProvider authenticates requests sent by a RestResourceHandler (see Config.AuthProvider).
+type AuthProvider interface {
+	// Authenticate adds credentials (e.g. the "Authorization" header) to the given request.
+	// The context of the request should be used for any calls needed to obtain the credentials.
+	Authenticate(req *http.Request) error
+	// Invalidate is called when the server rejected the credentials added to the given request
+	// (HTTP status 401). The provider should discard the credentials if they are cached,
+	// as the request is authenticated and sent once more.
+	Invalidate(req *http.Request)
+}
@@ -0,0 +1,244 @@
+package restresourcehandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed is the state in which all requests are sent and their outcomes are counted.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen is the state in which all requests are rejected with CircuitOpenError.
+	CircuitOpen
+	// CircuitHalfOpen is the state in which a limited number of probe requests is sent
+	// in order to decide if the circuit can be closed again.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig represents the configuration of a CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// Name is an optional name of the circuit (e.g. the resource endpoint),
+	// used in CircuitOpenError and passed to OnStateChange.
+	Name string
+	// FailureRatio is the ratio of failed requests (0 < FailureRatio <= 1) which opens the circuit.
+	// Transport errors and responses with HTTP status 5xx are failures.
+	FailureRatio float64
+	// MinRequests is the minimal number of requests within Window before FailureRatio is evaluated.
+	MinRequests int
+	// Window is the duration of the periods in which the requests are counted in the closed state.
+	Window time.Duration
+	// CoolDown is the duration for which the circuit stays open before it becomes half-open.
+	CoolDown time.Duration
+	// HalfOpenProbes is the number of probe requests sent in the half-open state.
+	// The circuit is closed once all the probes succeed and opened again once any of them fails.
+	HalfOpenProbes int
+	// OnStateChange is an optional callback called on every state transition (e.g. for alerting).
+	// It is called synchronously by the goroutine which caused the transition, so it should return quickly.
+	OnStateChange func(name string, from CircuitState, to CircuitState)
+}
+
+// validateCircuitBreakerConfig does a sanity check of a CircuitBreakerConfig instance.
+func validateCircuitBreakerConfig(config CircuitBreakerConfig) error {
+	if config.FailureRatio <= 0 || config.FailureRatio > 1 {
+		return &ConfigError{
+			"CircuitBreaker.FailureRatio",
+			"CircuitBreaker.FailureRatio must be greater than 0 and not greater than 1.",
+		}
+	}
+
+	if config.MinRequests < 1 {
+		return &ConfigError{"CircuitBreaker.MinRequests", "CircuitBreaker.MinRequests must be positive."}
+	}
+
+	if config.Window <= 0 {
+		return &ConfigError{"CircuitBreaker.Window", "CircuitBreaker.Window must be positive."}
+	}
+
+	if config.CoolDown <= 0 {
+		return &ConfigError{"CircuitBreaker.CoolDown", "CircuitBreaker.CoolDown must be positive."}
+	}
+
+	if config.HalfOpenProbes < 1 {
+		return &ConfigError{"CircuitBreaker.HalfOpenProbes", "CircuitBreaker.HalfOpenProbes must be positive."}
+	}
+
+	return nil
+}
+
+// CircuitBreaker stops sending requests to a failing resource endpoint (see Config.CircuitBreaker),
+// so that callers fail fast with CircuitOpenError instead of waiting for their timeouts.
+//
+// The circuit starts closed. It opens when the ratio of failed requests within a Window
+// reaches FailureRatio (after at least MinRequests requests). After CoolDown it becomes half-open
+// and lets HalfOpenProbes probe requests through: it closes when all of them succeed
+// and opens again when any of them fails.
+//
+// A CircuitBreaker is safe for concurrent use. Use NewCircuitBreaker to construct instances of CircuitBreaker.
+type CircuitBreaker struct {
+	mu          sync.Mutex
+	config      CircuitBreakerConfig
+	state       CircuitState
+	requests    int
+	failures    int
+	windowStart time.Time
+	openedAt    time.Time
+	probes      int
+	successes   int
+	generation  uint64
+	now         func() time.Time
+}
+
+// NewCircuitBreaker creates a closed CircuitBreaker.
+// Returns an error wrapping ErrInvalidConfig (see ConfigError) if the configuration is invalid.
+func NewCircuitBreaker(config CircuitBreakerConfig) (*CircuitBreaker, error) {
+	if err := validateCircuitBreakerConfig(config); err != nil {
+		return nil, err
+	}
+
+	return &CircuitBreaker{config: config, windowStart: time.Now(), now: time.Now}, nil
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := b.state
+	if state == CircuitOpen && !b.now().Before(b.openedAt.Add(b.config.CoolDown)) {
+		// the transition happens with the next request
+		state = CircuitHalfOpen
+	}
+
+	return state
+}
+
+// allow decides if a request can be sent.
+// Returns CircuitOpenError if the request is rejected. Otherwise the outcome of the request
+// must be reported with record, passing the returned generation of the circuit state.
+func (b *CircuitBreaker) allow() (uint64, error) {
+	b.mu.Lock()
+	from, now := b.state, b.now()
+
+	if b.state == CircuitOpen {
+		retryAt := b.openedAt.Add(b.config.CoolDown)
+		if now.Before(retryAt) {
+			b.mu.Unlock()
+
+			return 0, &CircuitOpenError{b.config.Name, retryAt}
+		}
+
+		b.setState(CircuitHalfOpen, now)
+	}
+
+	if b.state == CircuitHalfOpen {
+		if b.probes >= b.config.HalfOpenProbes {
+			b.mu.Unlock()
+			b.notify(from, CircuitHalfOpen)
+
+			return 0, &CircuitOpenError{b.config.Name, now}
+		}
+
+		b.probes++
+	}
+
+	to, generation := b.state, b.generation
+	b.mu.Unlock()
+	b.notify(from, to)
+
+	return generation, nil
+}
+
+// record reports the outcome of a request allowed by allow.
+// Outcomes of requests allowed before the last state transition are ignored.
+func (b *CircuitBreaker) record(ctx context.Context, generation uint64, resp *http.Response, err error) {
+	b.mu.Lock()
+	from, now := b.state, b.now()
+
+	if generation != b.generation {
+		b.mu.Unlock()
+
+		return
+	}
+
+	switch isFailure := isCircuitFailure(ctx, resp, err); {
+	case err != nil && !isFailure:
+		// the request has been cancelled by the caller - its outcome says nothing about the endpoint
+		if b.state == CircuitHalfOpen {
+			b.probes--
+		}
+	case b.state == CircuitHalfOpen && isFailure:
+		b.setState(CircuitOpen, now)
+	case b.state == CircuitHalfOpen:
+		b.successes++
+		if b.successes >= b.config.HalfOpenProbes {
+			b.setState(CircuitClosed, now)
+		}
+	case b.state == CircuitClosed:
+		b.count(isFailure, now)
+	}
+
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+}
+
+// count counts the outcome of a request in the closed state and opens the circuit if needed.
+func (b *CircuitBreaker) count(isFailure bool, now time.Time) {
+	if now.Sub(b.windowStart) >= b.config.Window {
+		b.requests, b.failures, b.windowStart = 0, 0, now
+	}
+
+	b.requests++
+	if isFailure {
+		b.failures++
+	}
+
+	if b.requests >= b.config.MinRequests && float64(b.failures)/float64(b.requests) >= b.config.FailureRatio {
+		b.setState(CircuitOpen, now)
+	}
+}
+
+func (b *CircuitBreaker) setState(state CircuitState, now time.Time) {
+	b.state = state
+	b.generation++
+	b.requests, b.failures, b.windowStart = 0, 0, now
+	b.probes, b.successes = 0, 0
+
+	if state == CircuitOpen {
+		b.openedAt = now
+	}
+}
+
+func (b *CircuitBreaker) notify(from CircuitState, to CircuitState) {
+	if from != to && b.config.OnStateChange != nil {
+		b.config.OnStateChange(b.config.Name, from, to)
+	}
+}
+
+// isCircuitFailure reports whether the outcome of a request denotes a failure of the endpoint.
+func isCircuitFailure(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(ctx.Err(), context.Canceled)
+	}
+
+	return resp.StatusCode >= http.StatusInternalServerError
+}
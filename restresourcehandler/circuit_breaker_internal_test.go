@@ -0,0 +1,214 @@
+package restresourcehandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func someValidCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		Name:           "people",
+		FailureRatio:   0.5,
+		MinRequests:    4,
+		Window:         time.Minute,
+		CoolDown:       10 * time.Second,
+		HalfOpenProbes: 2,
+	}
+}
+
+type stateChange struct {
+	From CircuitState
+	To   CircuitState
+}
+
+var _ = Describe("CircuitBreaker", func() {
+	var breaker *CircuitBreaker
+	var now time.Time
+	var changes []stateChange
+
+	succeed := func() {
+		generation, err := breaker.allow()
+		Expect(err).NotTo(HaveOccurred())
+		breaker.record(context.Background(), generation, responseWithStatus(http.StatusOK, nil), nil)
+	}
+
+	fail := func() {
+		generation, err := breaker.allow()
+		Expect(err).NotTo(HaveOccurred())
+		breaker.record(context.Background(), generation, responseWithStatus(http.StatusServiceUnavailable, nil), nil)
+	}
+
+	open := func() {
+		fail()
+		fail()
+		succeed()
+		succeed()
+		Expect(breaker.State()).To(Equal(CircuitOpen))
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2022, 1, 25, 12, 0, 0, 0, time.UTC)
+		changes = nil
+
+		config := someValidCircuitBreakerConfig()
+		config.OnStateChange = func(name string, from CircuitState, to CircuitState) {
+			Expect(name).To(Equal("people"))
+			changes = append(changes, stateChange{from, to})
+		}
+
+		var err error
+		breaker, err = NewCircuitBreaker(config)
+		Expect(err).NotTo(HaveOccurred())
+
+		breaker.now = func() time.Time { return now }
+		breaker.windowStart = now
+	})
+
+	DescribeTable("fails construction",
+		func(modify func(config *CircuitBreakerConfig), property string, message string) {
+			config := someValidCircuitBreakerConfig()
+			modify(&config)
+
+			_, err := NewCircuitBreaker(config)
+
+			Expect(err).To(MatchError(&ConfigError{property, message}))
+		},
+		Entry("when failure ratio is not positive",
+			func(config *CircuitBreakerConfig) { config.FailureRatio = 0 },
+			"CircuitBreaker.FailureRatio", "CircuitBreaker.FailureRatio must be greater than 0 and not greater than 1."),
+		Entry("when failure ratio is greater than 1",
+			func(config *CircuitBreakerConfig) { config.FailureRatio = 1.5 },
+			"CircuitBreaker.FailureRatio", "CircuitBreaker.FailureRatio must be greater than 0 and not greater than 1."),
+		Entry("when min requests is not positive",
+			func(config *CircuitBreakerConfig) { config.MinRequests = 0 },
+			"CircuitBreaker.MinRequests", "CircuitBreaker.MinRequests must be positive."),
+		Entry("when window is not positive",
+			func(config *CircuitBreakerConfig) { config.Window = 0 },
+			"CircuitBreaker.Window", "CircuitBreaker.Window must be positive."),
+		Entry("when cool-down is not positive",
+			func(config *CircuitBreakerConfig) { config.CoolDown = 0 },
+			"CircuitBreaker.CoolDown", "CircuitBreaker.CoolDown must be positive."),
+		Entry("when half-open probes is not positive",
+			func(config *CircuitBreakerConfig) { config.HalfOpenProbes = 0 },
+			"CircuitBreaker.HalfOpenProbes", "CircuitBreaker.HalfOpenProbes must be positive."),
+	)
+
+	It("stays closed below min requests", func() {
+		fail()
+		fail()
+		fail()
+
+		Expect(breaker.State()).To(Equal(CircuitClosed))
+		Expect(changes).To(BeEmpty())
+	})
+
+	It("stays closed below failure ratio", func() {
+		fail()
+		succeed()
+		succeed()
+		succeed()
+
+		Expect(breaker.State()).To(Equal(CircuitClosed))
+	})
+
+	It("forgets outcomes from previous windows", func() {
+		fail()
+		fail()
+		fail()
+
+		now = now.Add(time.Minute)
+		fail()
+
+		Expect(breaker.State()).To(Equal(CircuitClosed))
+	})
+
+	It("does not count requests cancelled by the caller", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		for i := 0; i < 4; i++ {
+			generation, err := breaker.allow()
+			Expect(err).NotTo(HaveOccurred())
+			breaker.record(ctx, generation, nil, context.Canceled)
+		}
+
+		Expect(breaker.State()).To(Equal(CircuitClosed))
+	})
+
+	It("counts transport errors as failures", func() {
+		for i := 0; i < 4; i++ {
+			generation, err := breaker.allow()
+			Expect(err).NotTo(HaveOccurred())
+			breaker.record(context.Background(), generation, nil, errors.New("connection refused"))
+		}
+
+		Expect(breaker.State()).To(Equal(CircuitOpen))
+	})
+
+	It("opens at failure ratio and rejects requests until cool-down passes", func() {
+		open()
+
+		_, err := breaker.allow()
+
+		Expect(err).To(MatchError(ErrCircuitOpen))
+		Expect(err).To(MatchError(&CircuitOpenError{"people", now.Add(10 * time.Second)}))
+		Expect(changes).To(Equal([]stateChange{{CircuitClosed, CircuitOpen}}))
+	})
+
+	It("closes after successful half-open probes", func() {
+		open()
+		now = now.Add(10 * time.Second)
+
+		Expect(breaker.State()).To(Equal(CircuitHalfOpen))
+
+		first, err := breaker.allow()
+		Expect(err).NotTo(HaveOccurred())
+		second, err := breaker.allow()
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = breaker.allow()
+		Expect(err).To(MatchError(ErrCircuitOpen))
+
+		breaker.record(context.Background(), first, responseWithStatus(http.StatusOK, nil), nil)
+		Expect(breaker.State()).To(Equal(CircuitHalfOpen))
+
+		breaker.record(context.Background(), second, responseWithStatus(http.StatusNotFound, nil), nil)
+		Expect(breaker.State()).To(Equal(CircuitClosed))
+
+		Expect(changes).To(Equal([]stateChange{
+			{CircuitClosed, CircuitOpen},
+			{CircuitOpen, CircuitHalfOpen},
+			{CircuitHalfOpen, CircuitClosed},
+		}))
+	})
+
+	It("opens again after failed half-open probe", func() {
+		open()
+		now = now.Add(10 * time.Second)
+
+		fail()
+
+		Expect(breaker.State()).To(Equal(CircuitOpen))
+
+		_, err := breaker.allow()
+		Expect(err).To(MatchError(&CircuitOpenError{"people", now.Add(10 * time.Second)}))
+	})
+
+	It("ignores outcomes of requests allowed before state transition", func() {
+		generation, err := breaker.allow()
+		Expect(err).NotTo(HaveOccurred())
+
+		open()
+		now = now.Add(10 * time.Second)
+		succeed()
+
+		breaker.record(context.Background(), generation, responseWithStatus(http.StatusOK, nil), nil)
+
+		Expect(breaker.State()).To(Equal(CircuitHalfOpen))
+	})
+})
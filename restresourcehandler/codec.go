@@ -0,0 +1,392 @@
+package restresourcehandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Codec encodes resources sent to and decodes resources received from a REST API
+// (see Config.Codec and Config.AcceptedCodecs).
+type Codec interface {
+	// ContentType is the content type of the encoding (e.g. "application/json"),
+	// sent in the "Content-Type" and "Accept" headers.
+	ContentType() string
+	// Marshal encodes v.
+	Marshal(v interface{}) ([]byte, error)
+	// Decode decodes the data read from reader into the value pointed to by v.
+	Decode(reader io.Reader, v interface{}) error
+	// Wrap encodes v nested in an envelope under the given property name (e.g. {"data": v}).
+	Wrap(propertyName string, v interface{}) ([]byte, error)
+	// Unwrap decodes the properties of an envelope (see Wrap) read from reader
+	// into the values pointed to by the given map (property name -> pointer to the value).
+	// The envelope should be decoded in a streaming manner, i.e. without reading it into memory as a whole.
+	// Returns the names of the properties which have not been found in the envelope (sorted).
+	Unwrap(reader io.Reader, properties map[string]interface{}) ([]string, error)
+}
+
+// RawValue is a raw, undecoded value of an envelope property (e.g. the "meta" property of a response).
+// The built-in codecs store the encoded property in it as is
+// (the JSON value or the inner XML of the element, respectively).
+type RawValue []byte
+
+// MarshalJSON returns the raw value (null if it is empty).
+func (v RawValue) MarshalJSON() ([]byte, error) {
+	if len(v) == 0 {
+		return []byte("null"), nil
+	}
+
+	return v, nil
+}
+
+// UnmarshalJSON stores a copy of the raw JSON value.
+func (v *RawValue) UnmarshalJSON(data []byte) error {
+	*v = append((*v)[:0], data...)
+
+	return nil
+}
+
+// UnmarshalXML stores the inner XML of the element.
+func (v *RawValue) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var element struct {
+		InnerXML []byte `xml:",innerxml"`
+	}
+
+	if err := decoder.DecodeElement(&element, &start); err != nil {
+		return err //nolint:wrapcheck // the caller wraps this error
+	}
+
+	*v = element.InnerXML
+
+	return nil
+}
+
+// JSONCodec is a Codec using encoding/json.
+// Envelopes are JSON objects with the wrapped values as properties.
+type JSONCodec struct {
+	// MediaType is the content type of the encoding (empty - "application/json").
+	MediaType string
+}
+
+// ContentType returns the content type of the encoding.
+func (c JSONCodec) ContentType() string {
+	if c.MediaType == "" {
+		return "application/json"
+	}
+
+	return c.MediaType
+}
+
+// Marshal encodes v as JSON.
+func (c JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v) //nolint:wrapcheck // the caller wraps this error
+}
+
+// Decode decodes JSON data into the value pointed to by v.
+func (c JSONCodec) Decode(reader io.Reader, v interface{}) error {
+	return json.NewDecoder(reader).Decode(v) //nolint:wrapcheck // the caller wraps this error
+}
+
+// Wrap encodes v as a property of a JSON object.
+func (c JSONCodec) Wrap(propertyName string, v interface{}) ([]byte, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // the caller wraps this error
+	}
+
+	return json.Marshal(map[string]json.RawMessage{propertyName: payload}) //nolint:wrapcheck // as above
+}
+
+// Unwrap decodes the properties of a JSON object.
+// The property values are decoded directly from the stream, other properties are skipped token by token.
+func (c JSONCodec) Unwrap(reader io.Reader, properties map[string]interface{}) ([]string, error) {
+	decoder := json.NewDecoder(reader)
+	found := map[string]bool{}
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err //nolint:wrapcheck // the caller wraps this error
+	}
+
+	if token != json.Delim('{') {
+		return nil, InvalidResponseError("envelope is not a JSON object")
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err //nolint:wrapcheck // the caller wraps this error
+		}
+
+		name, _ := token.(string)
+
+		value, ok := properties[name]
+		if !ok || found[name] {
+			err = skipJSONValue(decoder)
+		} else {
+			err = decodeJSONValue(decoder, value)
+			found[name] = true
+		}
+
+		if err != nil {
+			return nil, err //nolint:wrapcheck // the caller wraps this error
+		}
+	}
+
+	// the closing delimiter of the envelope
+	if _, err := decoder.Token(); err != nil {
+		return nil, err //nolint:wrapcheck // the caller wraps this error
+	}
+
+	return missingProperties(properties, found), nil
+}
+
+// decodeJSONValue decodes the next JSON value from decoder into the value pointed to by v.
+// Arrays decoded into slices are decoded element by element, so that only one element is buffered at a time.
+func decodeJSONValue(decoder *json.Decoder, v interface{}) error {
+	slice := reflect.ValueOf(v)
+	if !isStreamableSlice(slice) {
+		return decoder.Decode(v) //nolint:wrapcheck // the caller wraps this error
+	}
+
+	slice = slice.Elem()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return err //nolint:wrapcheck // the caller wraps this error
+	}
+
+	switch token {
+	case nil:
+		slice.Set(reflect.Zero(slice.Type()))
+
+		return nil
+	case json.Delim('['):
+	default:
+		return InvalidResponseError(fmt.Sprintf("cannot decode %v into %s", token, slice.Type()))
+	}
+
+	elements := reflect.MakeSlice(slice.Type(), 0, 0)
+
+	for decoder.More() {
+		element := reflect.New(slice.Type().Elem())
+		if err := decoder.Decode(element.Interface()); err != nil {
+			return err //nolint:wrapcheck // the caller wraps this error
+		}
+
+		elements = reflect.Append(elements, element.Elem())
+	}
+
+	// the closing delimiter of the array
+	if _, err := decoder.Token(); err != nil {
+		return err //nolint:wrapcheck // the caller wraps this error
+	}
+
+	slice.Set(elements)
+
+	return nil
+}
+
+// isStreamableSlice reports if value is a pointer to a slice decoded by encoding/json as a JSON array
+// (i.e. not a []byte and not a type with custom unmarshalling).
+func isStreamableSlice(value reflect.Value) bool {
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Slice {
+		return false
+	}
+
+	if value.Elem().Type().Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+
+	_, isUnmarshaler := value.Interface().(json.Unmarshaler)
+
+	return !isUnmarshaler
+}
+
+// skipJSONValue reads the next JSON value from decoder without decoding it.
+func skipJSONValue(decoder *json.Decoder) error {
+	for depth := 0; ; {
+		token, err := decoder.Token()
+		if err != nil {
+			return err //nolint:wrapcheck // the caller wraps this error
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// JSONAPICodec is a Codec for the JSON:API encoding ("application/vnd.api+json", https://jsonapi.org).
+type JSONAPICodec struct {
+	JSONCodec
+}
+
+// ContentType returns "application/vnd.api+json".
+func (c JSONAPICodec) ContentType() string {
+	return "application/vnd.api+json"
+}
+
+// XMLCodec is a Codec using encoding/xml.
+// Envelopes are XML documents with the wrapped values as child elements of the root element,
+// e.g. <envelope><data>...</data></envelope>.
+// The wrapped values take the names of the envelope properties, so their types must not
+// specify the element name (i.e. have an XMLName field with a name in its tag).
+type XMLCodec struct {
+	// MediaType is the content type of the encoding (empty - "application/xml").
+	MediaType string
+	// EnvelopeElement is the name of the root element of envelopes (empty - "envelope").
+	EnvelopeElement string
+}
+
+// ContentType returns the content type of the encoding.
+func (c XMLCodec) ContentType() string {
+	if c.MediaType == "" {
+		return "application/xml"
+	}
+
+	return c.MediaType
+}
+
+// Marshal encodes v as XML.
+func (c XMLCodec) Marshal(v interface{}) ([]byte, error) {
+	return xml.Marshal(v) //nolint:wrapcheck // the caller wraps this error
+}
+
+// Decode decodes XML data into the value pointed to by v.
+func (c XMLCodec) Decode(reader io.Reader, v interface{}) error {
+	return xml.NewDecoder(reader).Decode(v) //nolint:wrapcheck // the caller wraps this error
+}
+
+// Wrap encodes v as a child element (named propertyName) of the envelope root element.
+func (c XMLCodec) Wrap(propertyName string, v interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	encoder := xml.NewEncoder(&buffer)
+	root := xml.StartElement{Name: xml.Name{Local: c.envelopeElement()}}
+
+	if err := encoder.EncodeToken(root); err != nil {
+		return nil, err //nolint:wrapcheck // the caller wraps this error
+	}
+
+	if err := encoder.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: propertyName}}); err != nil {
+		return nil, err //nolint:wrapcheck // the caller wraps this error
+	}
+
+	if err := encoder.EncodeToken(root.End()); err != nil {
+		return nil, err //nolint:wrapcheck // the caller wraps this error
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return nil, err //nolint:wrapcheck // the caller wraps this error
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Unwrap decodes the child elements of the envelope root element
+// (the name of the root element is not checked).
+func (c XMLCodec) Unwrap(reader io.Reader, properties map[string]interface{}) ([]string, error) {
+	decoder := xml.NewDecoder(reader)
+	found := map[string]bool{}
+
+	if err := skipToStartElement(decoder); err != nil {
+		return nil, err
+	}
+
+	for depth := 1; depth > 0; {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err //nolint:wrapcheck // the caller wraps this error
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			value, ok := properties[element.Name.Local]
+			if !ok || found[element.Name.Local] {
+				err = decoder.Skip()
+			} else {
+				err = decoder.DecodeElement(value, &element)
+				found[element.Name.Local] = true
+			}
+
+			if err != nil {
+				return nil, err //nolint:wrapcheck // the caller wraps this error
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	return missingProperties(properties, found), nil
+}
+
+func (c XMLCodec) envelopeElement() string {
+	if c.EnvelopeElement == "" {
+		return "envelope"
+	}
+
+	return c.EnvelopeElement
+}
+
+// skipToStartElement reads tokens up to (and including) the root element of an XML document.
+func skipToStartElement(decoder *xml.Decoder) error {
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+
+		if err != nil {
+			return err //nolint:wrapcheck // the caller wraps this error
+		}
+
+		if _, ok := token.(xml.StartElement); ok {
+			return nil
+		}
+	}
+}
+
+// missingProperties returns the sorted names of the properties which have not been found.
+func missingProperties(properties map[string]interface{}, found map[string]bool) []string {
+	var missing []string
+
+	for name := range properties {
+		if !found[name] {
+			missing = append(missing, name)
+		}
+	}
+
+	sort.Strings(missing)
+
+	return missing
+}
+
+// isSameMediaType reports if the given content types denote the same media type (ignoring parameters).
+func isSameMediaType(contentType string, otherContentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	otherMediaType, _, err := mime.ParseMediaType(otherContentType)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(mediaType, otherMediaType)
+}
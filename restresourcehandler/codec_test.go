@@ -0,0 +1,168 @@
+package restresourcehandler_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type document struct {
+	Title string `json:"title" xml:"title"`
+	Pages int    `json:"pages" xml:"pages,attr"`
+}
+
+var _ = Describe("Codec", func() {
+	DescribeTable("has content type",
+		func(codec restresourcehandler.Codec, expectedContentType string) {
+			Expect(codec.ContentType()).To(Equal(expectedContentType))
+		},
+		Entry("json", restresourcehandler.JSONCodec{}, "application/json"),
+		Entry("json with custom media type",
+			restresourcehandler.JSONCodec{MediaType: "application/json; charset=utf-8"}, "application/json; charset=utf-8"),
+		Entry("json:api", restresourcehandler.JSONAPICodec{}, "application/vnd.api+json"),
+		Entry("xml", restresourcehandler.XMLCodec{}, "application/xml"),
+		Entry("xml with custom media type", restresourcehandler.XMLCodec{MediaType: "text/xml"}, "text/xml"),
+	)
+
+	DescribeTable("marshals and unmarshals resource",
+		func(codec restresourcehandler.Codec, expectedPayload string) {
+			payload, err := codec.Marshal(document{Title: "Report", Pages: 3})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(payload)).To(Equal(expectedPayload))
+
+			var decoded document
+			Expect(codec.Decode(bytes.NewReader(payload), &decoded)).To(Succeed())
+			Expect(decoded).To(Equal(document{Title: "Report", Pages: 3}))
+		},
+		Entry("json", restresourcehandler.JSONCodec{}, `{"title":"Report","pages":3}`),
+		Entry("json:api", restresourcehandler.JSONAPICodec{}, `{"title":"Report","pages":3}`),
+		Entry("xml", restresourcehandler.XMLCodec{}, `<document pages="3"><title>Report</title></document>`),
+	)
+
+	DescribeTable("wraps and unwraps resource",
+		func(codec restresourcehandler.Codec, expectedPayload string) {
+			payload, err := codec.Wrap("data", document{Title: "Report", Pages: 3})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(payload)).To(Equal(expectedPayload))
+
+			var decoded document
+			var links restresourcehandler.Links
+			missing, err := codec.Unwrap(
+				bytes.NewReader(payload),
+				map[string]interface{}{"data": &decoded, "links": &links})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(missing).To(Equal([]string{"links"}))
+			Expect(decoded).To(Equal(document{Title: "Report", Pages: 3}))
+		},
+		Entry("json", restresourcehandler.JSONCodec{}, `{"data":{"title":"Report","pages":3}}`),
+		Entry("json:api", restresourcehandler.JSONAPICodec{}, `{"data":{"title":"Report","pages":3}}`),
+		Entry("xml", restresourcehandler.XMLCodec{}, `<envelope><data pages="3"><title>Report</title></data></envelope>`),
+		Entry("xml with custom envelope element",
+			restresourcehandler.XMLCodec{EnvelopeElement: "response"},
+			`<response><data pages="3"><title>Report</title></data></response>`),
+	)
+
+	It("unwraps json envelope properties skipping other properties", func() {
+		payload := strings.NewReader(`{
+			"meta": {"data": [1, {"data": "ignored"}], "total": null},
+			"data": {"title": "Report", "pages": 3},
+			"included": [{"title": "Other"}],
+			"links": {"self": "/documents/1", "next": "/documents/2"}
+		}`)
+
+		var decoded document
+		var links restresourcehandler.Links
+		missing, err := restresourcehandler.JSONCodec{}.Unwrap(
+			payload,
+			map[string]interface{}{"data": &decoded, "links": &links})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(missing).To(BeEmpty())
+		Expect(decoded).To(Equal(document{Title: "Report", Pages: 3}))
+		Expect(links).To(Equal(restresourcehandler.Links{Self: "/documents/1", Next: "/documents/2"}))
+	})
+
+	DescribeTable("unwraps json array into slice",
+		func(payload string, expected []document) {
+			decoded := []document{{Title: "Previous"}}
+			_, err := restresourcehandler.JSONCodec{}.Unwrap(
+				strings.NewReader(payload),
+				map[string]interface{}{"data": &decoded})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decoded).To(Equal(expected))
+		},
+		Entry("with elements",
+			`{"data":[{"title":"First","pages":1},{"title":"Second","pages":2}]}`,
+			[]document{{Title: "First", Pages: 1}, {Title: "Second", Pages: 2}}),
+		Entry("without elements", `{"data":[]}`, []document{}),
+		Entry("being null", `{"data":null}`, []document(nil)),
+	)
+
+	It("fails to unwrap json object into slice", func() {
+		var decoded []document
+		_, err := restresourcehandler.JSONCodec{}.Unwrap(
+			strings.NewReader(`{"data":{"title":"Report"}}`),
+			map[string]interface{}{"data": &decoded})
+
+		Expect(err).To(MatchError(restresourcehandler.ErrInvalidResponse))
+	})
+
+	It("unwraps nested xml envelope properties", func() {
+		payload := strings.NewReader(`<?xml version="1.0"?>
+			<response>
+				<meta><data>ignored</data></meta>
+				<data pages="3"><title>Report</title></data>
+				<links><self>/documents/1</self><next>/documents/2</next></links>
+			</response>`)
+
+		var decoded document
+		var links restresourcehandler.Links
+		missing, err := restresourcehandler.XMLCodec{}.Unwrap(
+			payload,
+			map[string]interface{}{"data": &decoded, "links": &links})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(missing).To(BeEmpty())
+		Expect(decoded.Title).To(Equal("Report"))
+		Expect(links).To(Equal(restresourcehandler.Links{Self: "/documents/1", Next: "/documents/2"}))
+	})
+
+	DescribeTable("unwraps raw value",
+		func(codec restresourcehandler.Codec, payload string, expected string) {
+			var meta restresourcehandler.RawValue
+			_, err := codec.Unwrap(strings.NewReader(payload), map[string]interface{}{"meta": &meta})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(meta)).To(Equal(expected))
+		},
+		Entry("json", restresourcehandler.JSONCodec{}, `{"meta": {"total": [1, 2]}}`, `{"total": [1, 2]}`),
+		Entry("xml", restresourcehandler.XMLCodec{}, `<envelope><meta><total>1</total></meta></envelope>`, `<total>1</total>`),
+	)
+
+	It("marshals raw value as is", func() {
+		payload, err := restresourcehandler.JSONCodec{}.Marshal(map[string]restresourcehandler.RawValue{
+			"meta":  restresourcehandler.RawValue(`{"total":1}`),
+			"empty": nil,
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(payload)).To(Equal(`{"empty":null,"meta":{"total":1}}`))
+	})
+
+	DescribeTable("fails to unwrap malformed payload",
+		func(codec restresourcehandler.Codec, payload string) {
+			var decoded document
+			_, err := codec.Unwrap(strings.NewReader(payload), map[string]interface{}{"data": &decoded})
+
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("json", restresourcehandler.JSONCodec{}, `{"data":`),
+		Entry("json other than object", restresourcehandler.JSONCodec{}, `["data"]`),
+		Entry("xml", restresourcehandler.XMLCodec{}, `<envelope><data>`),
+		Entry("empty xml", restresourcehandler.XMLCodec{}, ``),
+	)
+})
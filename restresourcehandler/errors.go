@@ -4,19 +4,207 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // ErrRemoteError is a static error wrapped by all errors related to
 // the remote server returning an error response.
 var ErrRemoteError = errors.New("remote server returned an error")
 
-// RemoteError constructs an error for the given HTTP status code.
-func RemoteError(httpStatusCode int) error {
-	return fmt.Errorf(
-		"%w: http status code \"%d: %s\"",
+// ErrInvalidConfig is a static error wrapped by all errors related to
+// invalid RestResourceHandler configuration (see ConfigError).
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// ConfigError describes a problem with a RestResourceHandler configuration property.
+// ConfigError wraps ErrInvalidConfig.
+type ConfigError struct {
+	// Property is the name of the invalid property (e.g. "DataPropertyName").
+	Property string
+	// Message describes the problem.
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalidConfig, e.Message)
+}
+
+// Is reports that ConfigError wraps ErrInvalidConfig.
+func (e *ConfigError) Is(target error) bool {
+	return target == ErrInvalidConfig //nolint:errorlint,goerr113 // we compare with the sentinel on purpose
+}
+
+// ErrInvalidRequestParams is a static error wrapped by all errors related to
+// invalid parameters of a request.
+var ErrInvalidRequestParams = errors.New("invalid request parameters")
+
+// RequestParamsError constructs an error for a given error message.
+func RequestParamsError(message string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRequestParams, message)
+}
+
+// ErrInvalidResponse is a static error wrapped by all errors related to
+// a successful server response which cannot be read (e.g. lacks the data property).
+var ErrInvalidResponse = errors.New("invalid response")
+
+// InvalidResponseError constructs an error for a given error message.
+func InvalidResponseError(message string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidResponse, message)
+}
+
+// ErrResponseTooLarge is a static error wrapped by all errors related to
+// a server response exceeding the maximal response size (see ResponseSizeError).
+var ErrResponseTooLarge = errors.New("response too large")
+
+// ResponseSizeError is an error describing a response exceeding Config.MaxResponseSize.
+// ResponseSizeError wraps ErrResponseTooLarge.
+type ResponseSizeError struct {
+	// Limit is the maximal response size (in bytes).
+	Limit int64
+}
+
+func (e *ResponseSizeError) Error() string {
+	return fmt.Sprintf("%s: response exceeds the limit of %d bytes", ErrResponseTooLarge, e.Limit)
+}
+
+// Is reports that ResponseSizeError wraps ErrResponseTooLarge.
+func (e *ResponseSizeError) Is(target error) bool {
+	return target == ErrResponseTooLarge //nolint:errorlint,goerr113 // we compare with the sentinel on purpose
+}
+
+// ErrInvalidTraceparent is a static error wrapped by all errors related to
+// parsing malformed "traceparent" headers (see ParseTraceparent).
+var ErrInvalidTraceparent = errors.New("invalid traceparent")
+
+// InvalidTraceparentError constructs an error for a given error message.
+func InvalidTraceparentError(message string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidTraceparent, message)
+}
+
+// ErrCircuitOpen is a static error wrapped by all errors related to
+// a request rejected by an open circuit breaker (see CircuitOpenError).
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError is an error describing a request rejected without being sent
+// because the circuit breaker of the resource is open (see CircuitBreaker).
+// CircuitOpenError wraps ErrCircuitOpen.
+type CircuitOpenError struct {
+	// Name is the name of the circuit breaker (see CircuitBreakerConfig.Name).
+	Name string
+	// RetryAt is the earliest time a request may be let through again.
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%s: requests are rejected until %s", ErrCircuitOpen, e.RetryAt.Format(time.RFC3339))
+	}
+
+	return fmt.Sprintf(
+		`%s: requests to "%s" are rejected until %s`, ErrCircuitOpen, e.Name, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is reports that CircuitOpenError wraps ErrCircuitOpen.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen //nolint:errorlint,goerr113 // we compare with the sentinel on purpose
+}
+
+// MaxRemoteErrorBodyLength is the maximal length of the response body excerpt stored in RemoteError.
+// RemoteErrorExtractors should not read more than that from error responses.
+const MaxRemoteErrorBodyLength = 1024
+
+// RemoteError is an error describing an error response of the remote server.
+// RemoteError wraps ErrRemoteError, i.e. errors.Is(err, ErrRemoteError) holds for it.
+// Use errors.As to access its details:
+//
+//	var remoteErr *restresourcehandler.RemoteError
+//	if errors.As(err, &remoteErr) {
+//		// remoteErr.StatusCode, ...
+//	}
+type RemoteError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// ServerMessage is the error message sent by the server (empty if there was none).
+	ServerMessage string
+	// ErrorCode is the error code sent by the server (empty if there was none).
+	ErrorCode string
+	// Method is the HTTP method of the failed request.
+	Method string
+	// URL is the URL of the failed request.
+	URL string
+	// Header contains the headers of the response.
+	Header http.Header
+	// Body is an excerpt of the response body (at most 1024 bytes).
+	Body string
+}
+
+// NewRemoteError constructs a RemoteError for the given error response and its (already read) body.
+// ServerMessage and ErrorCode are left empty, as extracting them depends on the API.
+func NewRemoteError(response *http.Response, body []byte) *RemoteError {
+	remoteError := RemoteError{
+		StatusCode: response.StatusCode,
+		Header:     response.Header,
+	}
+
+	if response.Request != nil {
+		remoteError.Method = response.Request.Method
+		remoteError.URL = response.Request.URL.String()
+	}
+
+	if len(body) > MaxRemoteErrorBodyLength {
+		body = body[:MaxRemoteErrorBodyLength]
+	}
+
+	remoteError.Body = string(body)
+
+	return &remoteError
+}
+
+func (e *RemoteError) Error() string {
+	message := fmt.Sprintf(
+		"%s: http status code \"%d: %s\"",
 		ErrRemoteError,
-		httpStatusCode,
-		http.StatusText(httpStatusCode))
+		e.StatusCode,
+		http.StatusText(e.StatusCode))
+
+	if e.ServerMessage != "" {
+		message += fmt.Sprintf(", server message: \"%s\"", e.ServerMessage)
+	}
+
+	return message
+}
+
+// Is reports that RemoteError wraps ErrRemoteError.
+func (e *RemoteError) Is(target error) bool {
+	return target == ErrRemoteError //nolint:errorlint,goerr113 // we compare with the sentinel on purpose
+}
+
+// IsNotFound reports whether err is a RemoteError with HTTP status 404 (Not Found).
+func IsNotFound(err error) bool {
+	return hasRemoteStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is a RemoteError with HTTP status 409 (Conflict).
+func IsConflict(err error) bool {
+	return hasRemoteStatus(err, http.StatusConflict)
+}
+
+// IsRateLimited reports whether err is a RemoteError with HTTP status 429 (Too Many Requests).
+func IsRateLimited(err error) bool {
+	return hasRemoteStatus(err, http.StatusTooManyRequests)
+}
+
+// IsRetryable reports whether err is a RemoteError with an HTTP status
+// denoting a transient failure (429 or 5xx), i.e. the request may succeed when retried.
+func IsRetryable(err error) bool {
+	var remoteError *RemoteError
+
+	return errors.As(err, &remoteError) && isRetryableStatus(remoteError.StatusCode)
+}
+
+func hasRemoteStatus(err error, statusCode int) bool {
+	var remoteError *RemoteError
+
+	return errors.As(err, &remoteError) && remoteError.StatusCode == statusCode
 }
 
 // WrapError wraps an external error and decorates it with an additional message.
@@ -0,0 +1,91 @@
+package restresourcehandler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func someErrorResponse(statusCode int) *http.Response {
+	requestURL, err := url.Parse("http://example.com/api/people/1")
+	if err != nil {
+		panic(err)
+	}
+
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"text/plain"}},
+		Request:    &http.Request{Method: http.MethodGet, URL: requestURL},
+	}
+}
+
+var _ = Describe("RemoteError", func() {
+	It("is constructed from error response", func() {
+		remoteError := restresourcehandler.NewRemoteError(someErrorResponse(http.StatusNotFound), []byte("not found"))
+
+		Expect(remoteError).To(Equal(&restresourcehandler.RemoteError{
+			StatusCode: http.StatusNotFound,
+			Method:     http.MethodGet,
+			URL:        "http://example.com/api/people/1",
+			Header:     http.Header{"Content-Type": []string{"text/plain"}},
+			Body:       "not found",
+		}))
+	})
+
+	It("stores only an excerpt of a long body", func() {
+		remoteError := restresourcehandler.NewRemoteError(
+			someErrorResponse(http.StatusNotFound),
+			[]byte(strings.Repeat("a", 2000)))
+
+		Expect(remoteError.Body).To(HaveLen(1024))
+	})
+
+	It("wraps ErrRemoteError", func() {
+		var err error = restresourcehandler.NewRemoteError(someErrorResponse(http.StatusNotFound), nil)
+
+		Expect(err).To(MatchError(restresourcehandler.ErrRemoteError))
+		Expect(fmt.Errorf("wrapped: %w", err)).To(MatchError(restresourcehandler.ErrRemoteError))
+	})
+
+	It("describes status code and server message", func() {
+		remoteError := restresourcehandler.NewRemoteError(someErrorResponse(http.StatusConflict), nil)
+
+		Expect(remoteError.Error()).
+			To(Equal(`remote server returned an error: http status code "409: Conflict"`))
+
+		remoteError.ServerMessage = "invalid version"
+
+		Expect(remoteError.Error()).
+			To(Equal(`remote server returned an error: http status code "409: Conflict", server message: "invalid version"`))
+	})
+
+	DescribeTable("predicates",
+		func(predicate func(error) bool, statusCode int, expected bool) {
+			err := fmt.Errorf("wrapped: %w", restresourcehandler.NewRemoteError(someErrorResponse(statusCode), nil))
+
+			Expect(predicate(err)).To(Equal(expected))
+		},
+		Entry("IsNotFound on 404", restresourcehandler.IsNotFound, http.StatusNotFound, true),
+		Entry("IsNotFound on 409", restresourcehandler.IsNotFound, http.StatusConflict, false),
+		Entry("IsConflict on 409", restresourcehandler.IsConflict, http.StatusConflict, true),
+		Entry("IsConflict on 400", restresourcehandler.IsConflict, http.StatusBadRequest, false),
+		Entry("IsRateLimited on 429", restresourcehandler.IsRateLimited, http.StatusTooManyRequests, true),
+		Entry("IsRateLimited on 503", restresourcehandler.IsRateLimited, http.StatusServiceUnavailable, false),
+		Entry("IsRetryable on 429", restresourcehandler.IsRetryable, http.StatusTooManyRequests, true),
+		Entry("IsRetryable on 503", restresourcehandler.IsRetryable, http.StatusServiceUnavailable, true),
+		Entry("IsRetryable on 400", restresourcehandler.IsRetryable, http.StatusBadRequest, false),
+	)
+
+	It("predicates do not match other errors", func() {
+		err := fmt.Errorf("some error") //nolint:goerr113 // not a problem here
+
+		Expect(restresourcehandler.IsNotFound(err)).To(BeFalse())
+		Expect(restresourcehandler.IsRetryable(err)).To(BeFalse())
+		Expect(restresourcehandler.IsNotFound(nil)).To(BeFalse())
+	})
+})
@@ -0,0 +1,300 @@
+package restresourcehandler
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	algorithmRSASHA256   = "rsa-sha256"
+	algorithmECDSASHA256 = "ecdsa-sha256"
+	digestPrefix         = "SHA-256="
+)
+
+// ErrInvalidSignature is a static error wrapped by all errors related to
+// a missing or invalid HTTP request signature.
+var ErrInvalidSignature = errors.New("invalid request signature")
+
+// SignatureError constructs an error for a given error message.
+func SignatureError(message string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidSignature, message)
+}
+
+// RequestSigner signs HTTP requests according to the HTTP Signatures scheme
+// (https://datatracker.ietf.org/doc/html/draft-cavage-http-signatures),
+// as required by the Form3 API.
+//
+// The signature covers the "(request-target)", "host" and "date" headers and - for requests
+// with a body - also the "content-type", "content-length" and "digest" headers.
+// The "digest" header contains the SHA-256 digest of the request body.
+//
+// Use NewRequestSigner to construct instances of RequestSigner.
+type RequestSigner struct {
+	keyID      string
+	privateKey crypto.Signer
+	algorithm  string
+}
+
+// NewRequestSigner constructs a RequestSigner for the given key id
+// and an RSA (*rsa.PrivateKey) or ECDSA (*ecdsa.PrivateKey) private key.
+func NewRequestSigner(keyID string, privateKey crypto.Signer) (*RequestSigner, error) {
+	if keyID == "" {
+		return nil, &ConfigError{"KeyID", "key id must be set"}
+	}
+
+	var algorithm string
+
+	switch privateKey.(type) {
+	case *rsa.PrivateKey:
+		algorithm = algorithmRSASHA256
+	case *ecdsa.PrivateKey:
+		algorithm = algorithmECDSASHA256
+	default:
+		return nil, &ConfigError{"PrivateKey", fmt.Sprintf("unsupported private key type %T", privateKey)}
+	}
+
+	return &RequestSigner{keyID: keyID, privateKey: privateKey, algorithm: algorithm}, nil
+}
+
+// Sign sets the "Date" (unless already set), "Digest" (for requests with a body)
+// and "Authorization" headers of the given request.
+// The request body is read using GetBody if available, otherwise it is replaced with an in-memory copy.
+func (s *RequestSigner) Sign(req *http.Request) error {
+	if req.Header.Get("Date") == "" {
+		req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	}
+
+	headers := []string{"(request-target)", "host", "date"}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := readRequestBody(req)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Digest", digest(body))
+		headers = append(headers, "content-type", "content-length", "digest")
+	}
+
+	hashed := sha256.Sum256([]byte(signingString(req, headers)))
+
+	signature, err := s.privateKey.Sign(rand.Reader, hashed[:], crypto.SHA256)
+	if err != nil {
+		return WrapError(err, "signing request")
+	}
+
+	req.Header.Set(
+		"Authorization",
+		fmt.Sprintf(
+			`Signature keyId="%s",algorithm="%s",headers="%s",signature="%s"`,
+			s.keyID,
+			s.algorithm,
+			strings.Join(headers, " "),
+			base64.StdEncoding.EncodeToString(signature)))
+
+	return nil
+}
+
+// SigningTransport is an http.RoundTripper which signs every request using Signer
+// before passing it to the Base http.RoundTripper.
+// The original request is not modified.
+type SigningTransport struct {
+	// Signer is used to sign the requests.
+	Signer *RequestSigner
+	// Base is the http.RoundTripper used to send the signed requests
+	// (nil - http.DefaultTransport).
+	Base http.RoundTripper
+}
+
+// RoundTrip signs and sends the given request.
+func (t *SigningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	signedReq := req.Clone(req.Context())
+
+	if err := t.Signer.Sign(signedReq); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+
+		return nil, err
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return base.RoundTrip(signedReq) //nolint:wrapcheck // transport errors are wrapped by http.Client
+}
+
+var signatureParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// VerifyRequestSignature verifies the signature of a request signed by a RequestSigner
+// (e.g. in a test server) using the given RSA (*rsa.PublicKey) or ECDSA (*ecdsa.PublicKey) public key.
+// Returns the key id the request has been signed with.
+// Returns an error wrapping ErrInvalidSignature if the signature or the body digest is invalid
+// or if the signature does not cover the "(request-target)", "host" and "date" headers
+// (and "digest" for requests with a body).
+func VerifyRequestSignature(req *http.Request, publicKey crypto.PublicKey) (string, error) {
+	authorization := req.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Signature ") {
+		return "", SignatureError("missing signature authorization header")
+	}
+
+	params := make(map[string]string)
+	for _, match := range signatureParamRegexp.FindAllStringSubmatch(authorization, -1) {
+		params[match[1]] = match[2]
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil || len(signature) == 0 {
+		return "", SignatureError("malformed signature")
+	}
+
+	headers := strings.Fields(params["headers"])
+	for _, required := range []string{"(request-target)", "host", "date"} {
+		if !contains(headers, required) {
+			return "", SignatureError(fmt.Sprintf("%s header is not signed", required))
+		}
+	}
+
+	if err := verifyDigest(req, headers); err != nil {
+		return "", err
+	}
+
+	hashed := sha256.Sum256([]byte(signingString(req, headers)))
+
+	if err := verifySignature(publicKey, params["algorithm"], hashed[:], signature); err != nil {
+		return "", err
+	}
+
+	return params["keyId"], nil
+}
+
+func verifyDigest(req *http.Request, signedHeaders []string) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return WrapError(err, "reading request body")
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if len(body) == 0 {
+		return nil
+	}
+
+	if !contains(signedHeaders, "digest") {
+		return SignatureError("digest header is not signed")
+	}
+
+	if req.Header.Get("Digest") != digest(body) {
+		return SignatureError("digest does not match request body")
+	}
+
+	return nil
+}
+
+func verifySignature(publicKey crypto.PublicKey, algorithm string, hashed []byte, signature []byte) error {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if algorithm != algorithmRSASHA256 || rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed, signature) != nil {
+			return SignatureError("signature verification failed")
+		}
+	case *ecdsa.PublicKey:
+		if algorithm != algorithmECDSASHA256 || !ecdsa.VerifyASN1(key, hashed, signature) {
+			return SignatureError("signature verification failed")
+		}
+	default:
+		return SignatureError(fmt.Sprintf("unsupported public key type %T", publicKey))
+	}
+
+	return nil
+}
+
+// signingString constructs the string to be signed for the given request and list of signed headers.
+func signingString(req *http.Request, headers []string) string {
+	lines := make([]string, len(headers))
+
+	for i, header := range headers {
+		var value string
+
+		switch header {
+		case "(request-target)":
+			value = strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			value = req.Host
+			if value == "" {
+				value = req.URL.Host
+			}
+		case "content-length":
+			value = strconv.FormatInt(req.ContentLength, 10)
+		default:
+			value = req.Header.Get(header)
+		}
+
+		lines[i] = header + ": " + value
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// readRequestBody reads the request body without consuming it.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.GetBody != nil {
+		bodyCopy, err := req.GetBody()
+		if err != nil {
+			return nil, WrapError(err, "reading request body")
+		}
+		defer bodyCopy.Close()
+
+		body, err := ioutil.ReadAll(bodyCopy)
+
+		return body, WrapError(err, "reading request body")
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, WrapError(err, "reading request body")
+	}
+
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	return body, nil
+}
+
+func digest(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return digestPrefix + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
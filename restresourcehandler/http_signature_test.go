@@ -0,0 +1,231 @@
+package restresourcehandler_test
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+const someKeyID = "75a8ba12-fff2-4a52-ad8a-e8b34c5ccec8"
+
+func verifySignature(publicKey crypto.PublicKey) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		keyID, err := restresourcehandler.VerifyRequestSignature(req, publicKey)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keyID).To(Equal(someKeyID))
+	}
+}
+
+var _ = Describe("HTTP request signing", func() {
+	var server *ghttp.Server
+	var rsaKey *rsa.PrivateKey
+	var ecdsaKey *ecdsa.PrivateKey
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+
+		var err error
+		rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+		ecdsaKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	signingClient := func(privateKey crypto.Signer) *http.Client {
+		signer, err := restresourcehandler.NewRequestSigner(someKeyID, privateKey)
+		Expect(err).NotTo(HaveOccurred())
+
+		return &http.Client{Transport: &restresourcehandler.SigningTransport{Signer: signer}}
+	}
+
+	Context("with signing transport", func() {
+		It("signs request with body using RSA key", func() {
+			client := restresourcehandler.MustNew(
+				signingClient(rsaKey),
+				server.URL()+"/api/people",
+				restresourcehandler.Config{ResourceEncoding: "application/json"})
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					verifySignature(&rsaKey.PublicKey),
+					ghttp.VerifyJSONRepresenting(person{"Smith"}),
+					func(w http.ResponseWriter, req *http.Request) {
+						Expect(req.Header.Get("Date")).NotTo(BeEmpty())
+						Expect(req.Header.Get("Digest")).To(HavePrefix("SHA-256="))
+						Expect(req.Header.Get("Authorization")).To(And(
+							HavePrefix(`Signature keyId="`+someKeyID+`",algorithm="rsa-sha256",`),
+							ContainSubstring(`headers="(request-target) host date content-type content-length digest"`)))
+					},
+					ghttp.RespondWithJSONEncoded(http.StatusCreated, person{"Smith"})))
+
+			var response person
+			err := client.Create(context.Background(), person{"Smith"}, &response)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("signs request without body using ECDSA key", func() {
+			client := restresourcehandler.MustNew(
+				signingClient(ecdsaKey),
+				server.URL()+"/api/people",
+				restresourcehandler.Config{ResourceEncoding: "application/json"})
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					verifySignature(&ecdsaKey.PublicKey),
+					func(w http.ResponseWriter, req *http.Request) {
+						Expect(req.Header.Get("Digest")).To(BeEmpty())
+						Expect(req.Header.Get("Authorization")).To(And(
+							ContainSubstring(`algorithm="ecdsa-sha256"`),
+							ContainSubstring(`headers="(request-target) host date"`)))
+					},
+					ghttp.RespondWith(http.StatusNoContent, nil)))
+
+			err := client.Delete(context.Background(), "1", map[string]string{"version": "0"})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not modify the original request", func() {
+			req, err := http.NewRequest(http.MethodPost, server.URL(), strings.NewReader(`{"name":"Smith"}`))
+			Expect(err).NotTo(HaveOccurred())
+			server.AppendHandlers(ghttp.RespondWith(http.StatusOK, nil))
+
+			resp, err := signingClient(rsaKey).Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+
+			Expect(req.Header.Get("Authorization")).To(BeEmpty())
+		})
+	})
+
+	Context("verification", func() {
+		signedRequest := func(privateKey crypto.Signer, body string) *http.Request {
+			req, err := http.NewRequest(http.MethodPost, "http://example.com/api/people?page=1", bytes.NewReader([]byte(body)))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Content-Type", "application/json")
+
+			signer, err := restresourcehandler.NewRequestSigner(someKeyID, privateKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(signer.Sign(req)).To(Succeed())
+
+			return req
+		}
+
+		It("accepts valid signature", func() {
+			keyID, err := restresourcehandler.VerifyRequestSignature(signedRequest(rsaKey, "{}"), &rsaKey.PublicKey)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keyID).To(Equal(someKeyID))
+		})
+
+		It("rejects signature made with another key", func() {
+			otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = restresourcehandler.VerifyRequestSignature(signedRequest(rsaKey, "{}"), &otherKey.PublicKey)
+
+			Expect(err).To(MatchError(restresourcehandler.SignatureError("signature verification failed")))
+		})
+
+		It("rejects request with tampered body", func() {
+			req := signedRequest(ecdsaKey, `{"name":"Smith"}`)
+			req.Body = ioutil.NopCloser(strings.NewReader(`{"name":"Jones"}`))
+
+			_, err := restresourcehandler.VerifyRequestSignature(req, &ecdsaKey.PublicKey)
+
+			Expect(err).To(MatchError(restresourcehandler.SignatureError("digest does not match request body")))
+		})
+
+		It("rejects request with tampered header", func() {
+			req := signedRequest(ecdsaKey, "{}")
+			req.Header.Set("Content-Type", "text/plain")
+
+			_, err := restresourcehandler.VerifyRequestSignature(req, &ecdsaKey.PublicKey)
+
+			Expect(err).To(MatchError(restresourcehandler.SignatureError("signature verification failed")))
+		})
+
+		// resignedRequest replaces the signature of the request with a valid one covering only the given headers.
+		resignedRequest := func(req *http.Request, signingString string, headers string) *http.Request {
+			hashed := sha256.Sum256([]byte(signingString))
+			signature, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, hashed[:])
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Authorization", fmt.Sprintf(
+				`Signature keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+				someKeyID,
+				headers,
+				base64.StdEncoding.EncodeToString(signature)))
+
+			return req
+		}
+
+		It("rejects signature covering no headers", func() {
+			req := resignedRequest(signedRequest(rsaKey, ""), "", "")
+
+			_, err := restresourcehandler.VerifyRequestSignature(req, &rsaKey.PublicKey)
+
+			Expect(err).To(MatchError(restresourcehandler.SignatureError("(request-target) header is not signed")))
+		})
+
+		It("rejects signature not covering date", func() {
+			req := resignedRequest(
+				signedRequest(rsaKey, ""),
+				"(request-target): post /api/people?page=1\nhost: example.com",
+				"(request-target) host")
+
+			_, err := restresourcehandler.VerifyRequestSignature(req, &rsaKey.PublicKey)
+
+			Expect(err).To(MatchError(restresourcehandler.SignatureError("date header is not signed")))
+		})
+
+		It("rejects signature not covering digest of request with body", func() {
+			req := signedRequest(rsaKey, "{}")
+			req = resignedRequest(
+				req,
+				"(request-target): post /api/people?page=1\nhost: example.com\ndate: "+req.Header.Get("Date"),
+				"(request-target) host date")
+
+			_, err := restresourcehandler.VerifyRequestSignature(req, &rsaKey.PublicKey)
+
+			Expect(err).To(MatchError(restresourcehandler.SignatureError("digest header is not signed")))
+		})
+
+		It("rejects unsigned request", func() {
+			req, err := http.NewRequest(http.MethodGet, "http://example.com/api/people", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = restresourcehandler.VerifyRequestSignature(req, &rsaKey.PublicKey)
+
+			Expect(err).To(MatchError(restresourcehandler.ErrInvalidSignature))
+		})
+	})
+
+	It("does not support other key types", func() {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = restresourcehandler.NewRequestSigner(someKeyID, privateKey)
+
+		Expect(err).To(MatchError(restresourcehandler.ErrInvalidConfig))
+	})
+})
@@ -0,0 +1,17 @@
+package restresourcehandler
+
+import "github.com/google/uuid"
+
+// IdempotencyKeyFunc is a function prototype for functions generating the idempotency key
+// of a create operation for the resource to be created (see Config.IdempotencyKey).
+type IdempotencyKeyFunc func(resource interface{}) (string, error)
+
+// RandomIdempotencyKey is an IdempotencyKeyFunc generating a random (version 4 UUID) key for every operation.
+func RandomIdempotencyKey(interface{}) (string, error) {
+	key, err := uuid.NewRandom()
+	if err != nil {
+		return "", WrapError(err, "generating idempotency key")
+	}
+
+	return key.String(), nil
+}
@@ -0,0 +1,137 @@
+package restresourcehandler
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the default upper bounds of the latency histogram buckets of InMemoryMetrics.
+var DefaultLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// OperationStats are the statistics of the operations with the same MetricLabels (see InMemoryMetrics).
+type OperationStats struct {
+	// Count is the number of operations.
+	Count uint64
+	// LatencyCount is the number of observed latencies. It is counted separately from Count,
+	// so that the latency histogram is consistent even if read between the two events of an operation.
+	LatencyCount uint64
+	// LatencySum is the sum of the latencies of the operations.
+	LatencySum time.Duration
+	// LatencyBuckets are the cumulative latency histogram buckets:
+	// LatencyBuckets[i] is the number of operations not longer than InMemoryMetrics.Buckets()[i].
+	LatencyBuckets []uint64
+}
+
+// OperationSeries are the statistics of the operations with the given labels (see InMemoryMetrics.Snapshot).
+type OperationSeries struct {
+	Labels MetricLabels
+	OperationStats
+}
+
+// InMemoryMetrics is a Metrics implementation keeping the statistics in memory.
+// The statistics can be read with Snapshot or exposed to Prometheus with PrometheusExporter.
+// Use NewInMemoryMetrics to construct instances of InMemoryMetrics.
+type InMemoryMetrics struct {
+	mu      sync.Mutex
+	buckets []time.Duration
+	stats   map[MetricLabels]*OperationStats
+}
+
+// NewInMemoryMetrics creates an InMemoryMetrics instance with the given latency histogram bucket upper bounds
+// (DefaultLatencyBuckets if none are given).
+func NewInMemoryMetrics(buckets ...time.Duration) *InMemoryMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+
+	sorted := append([]time.Duration(nil), buckets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &InMemoryMetrics{buckets: sorted, stats: map[MetricLabels]*OperationStats{}}
+}
+
+// CountOperation implements Metrics.
+func (m *InMemoryMetrics) CountOperation(labels MetricLabels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.statsFor(labels).Count++
+}
+
+// ObserveLatency implements Metrics.
+func (m *InMemoryMetrics) ObserveLatency(labels MetricLabels, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.statsFor(labels)
+	stats.LatencyCount++
+	stats.LatencySum += latency
+
+	for i, bound := range m.buckets {
+		if latency <= bound {
+			stats.LatencyBuckets[i]++
+		}
+	}
+}
+
+// Buckets returns the upper bounds of the latency histogram buckets.
+func (m *InMemoryMetrics) Buckets() []time.Duration {
+	return append([]time.Duration(nil), m.buckets...)
+}
+
+// Snapshot returns a copy of the current statistics, sorted by the labels.
+func (m *InMemoryMetrics) Snapshot() []OperationSeries {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	series := make([]OperationSeries, 0, len(m.stats))
+	for labels, stats := range m.stats {
+		stats := *stats
+		stats.LatencyBuckets = append([]uint64(nil), stats.LatencyBuckets...)
+		series = append(series, OperationSeries{labels, stats})
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		return lessLabels(series[i].Labels, series[j].Labels)
+	})
+
+	return series
+}
+
+func (m *InMemoryMetrics) statsFor(labels MetricLabels) *OperationStats {
+	stats, ok := m.stats[labels]
+	if !ok {
+		stats = &OperationStats{LatencyBuckets: make([]uint64, len(m.buckets))}
+		m.stats[labels] = stats
+	}
+
+	return stats
+}
+
+func lessLabels(a MetricLabels, b MetricLabels) bool {
+	for _, pair := range [][2]string{
+		{a.Resource, b.Resource},
+		{a.Method, b.Method},
+		{a.StatusClass, b.StatusClass},
+		{a.ErrorKind, b.ErrorKind},
+	} {
+		if pair[0] != pair[1] {
+			return pair[0] < pair[1]
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,55 @@
+package restresourcehandler
+
+import (
+	"context"
+	"net/http"
+)
+
+// Operation describes a single logical operation (e.g. fetching a resource) performed by a RestResourceHandler.
+// It is passed to interceptors (see Interceptor).
+type Operation struct {
+	// Method is the HTTP method of the operation ("GET", "DELETE", "POST" or "PATCH").
+	// It is informational - changing it does not change the request.
+	Method string
+	// ResourceURL is the URL of the handled resource (without the resource id).
+	// It is informational - changing it does not change the request.
+	ResourceURL string
+	// ResourceID is the id of the targeted resource (empty for operations on the collection, e.g. create or list).
+	ResourceID string
+	// QueryParams are the query params sent with the request.
+	// It is a copy of the params passed in by the caller, so it can be modified freely.
+	QueryParams map[string]string
+	// Resource is the object sent in the request (nil - no request content).
+	Resource interface{}
+	// Response is the object the response content is stored in (nil - no response content).
+	// After a successful call of the next Invoker it holds the decoded response.
+	Response interface{}
+	// IdempotencyKey is the key sent in the "Idempotency-Key" header of create operations
+	// (empty - no key is sent, see Config.IdempotencyKey).
+	IdempotencyKey string
+	// Header contains additional headers sent with the request.
+	Header http.Header
+}
+
+// Invoker performs an operation. See Interceptor.
+type Invoker func(ctx context.Context, op *Operation) error
+
+// Interceptor is a function prototype for functions intercepting operations performed by a RestResourceHandler
+// (see Config.Interceptors).
+// An interceptor may modify the operation before passing it to next (e.g. add headers),
+// inspect the result and the error returned by next, or short-circuit the operation by not calling next at all
+// (e.g. filling op.Response from a cache).
+type Interceptor func(ctx context.Context, op *Operation, next Invoker) error
+
+// chainInterceptors builds an Invoker calling the given interceptors (the first one is the outermost)
+// and finally the given invoker.
+func chainInterceptors(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, op *Operation) error {
+			return interceptor(ctx, op, next)
+		}
+	}
+
+	return invoker
+}
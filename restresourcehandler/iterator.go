@@ -0,0 +1,131 @@
+package restresourcehandler
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Iterator iterates over a paginated list of resources of type T (see TypedResource.List).
+// Pages are fetched lazily, i.e. only when the iteration goes past the last resource of the current page.
+//
+// Typical usage:
+//
+//	it := resource.List(ctx, map[string]string{"page[size]": "100"})
+//	for it.Next() {
+//		value := it.Value()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+type Iterator[T any] struct {
+	ctx     context.Context //nolint:containedctx // the iterator fetches pages lazily on behalf of List
+	handler *RestResourceHandler
+	// queryParams are the query params of the first page, used as defaults
+	// for the following pages (e.g. filters not repeated in the server links).
+	queryParams map[string]string
+	// nextPageQueryParams are the query params of the next page to be fetched (nil - no more pages).
+	nextPageQueryParams map[string]string
+	// nextPageErr is the error of parsing the next page link, reported once the current page is exhausted.
+	nextPageErr  error
+	page         []T
+	pageMetadata ResponseMetadata
+	current      T
+	err          error
+}
+
+// NewFailedIterator returns an iterator which yields no resources and reports the given error
+// (e.g. for listings with invalid parameters).
+func NewFailedIterator[T any](err error) *Iterator[T] {
+	return &Iterator[T]{err: err}
+}
+
+// Next advances the iterator to the next resource, fetching the next page if needed.
+// Returns false when there are no more resources or an error occurred (see Err).
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for len(it.page) == 0 {
+		if it.nextPageQueryParams == nil {
+			it.err = it.nextPageErr
+
+			return false
+		}
+
+		if it.err = it.fetchNextPage(); it.err != nil {
+			return false
+		}
+	}
+
+	it.current, it.page = it.page[0], it.page[1:]
+
+	return true
+}
+
+// Value returns the resource the iterator currently points at.
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+// PageMetadata returns the response metadata of the most recently fetched page
+// (also if fetching the page failed with an error response).
+func (it *Iterator[T]) PageMetadata() ResponseMetadata {
+	return it.pageMetadata
+}
+
+// Err returns the error that stopped the iteration (nil if there was none).
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+func (it *Iterator[T]) fetchNextPage() error {
+	if err := it.ctx.Err(); err != nil {
+		return WrapError(err, "fetching page")
+	}
+
+	var page []T
+
+	var err error
+	if it.pageMetadata, err = it.handler.ListWithMetadata(it.ctx, it.nextPageQueryParams, &page); err != nil {
+		return err
+	}
+
+	// the resources of the page are yielded even if the next page link is invalid
+	it.page = page
+	it.nextPageQueryParams, it.nextPageErr = nextPageQueryParams(it.pageMetadata.Links, it.queryParams)
+
+	return nil
+}
+
+// nextPageQueryParams extracts query params of the next page from the links sent along a page.
+// Params missing in the link are taken from defaultQueryParams.
+// Returns an error wrapping ErrInvalidResponse if the link repeats a param (repeated params are not supported).
+// Returns nil if there is no next page.
+func nextPageQueryParams(links Links, defaultQueryParams map[string]string) (map[string]string, error) {
+	if links.Next == "" {
+		return nil, nil //nolint:nilnil // nil query params denote there is no next page
+	}
+
+	nextURL, err := url.Parse(links.Next)
+	if err != nil {
+		return nil, WrapError(err, "parsing next page link")
+	}
+
+	queryParams := make(map[string]string, len(defaultQueryParams))
+	for key, value := range defaultQueryParams {
+		queryParams[key] = value
+	}
+
+	for key, values := range nextURL.Query() {
+		if len(values) > 1 {
+			return nil, InvalidResponseError(fmt.Sprintf(`next page link repeats query param "%s"`, key))
+		}
+
+		queryParams[key] = values[0]
+	}
+
+	return queryParams, nil
+}
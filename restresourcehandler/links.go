@@ -0,0 +1,12 @@
+package restresourcehandler
+
+// Links represents the HATEOAS links sent along a server response.
+// Empty properties denote links that have not been sent by the server
+// (e.g. Next is empty on the last page of a collection).
+type Links struct {
+	Self  string `json:"self,omitempty" xml:"self,omitempty"`
+	First string `json:"first,omitempty" xml:"first,omitempty"`
+	Prev  string `json:"prev,omitempty" xml:"prev,omitempty"`
+	Next  string `json:"next,omitempty" xml:"next,omitempty"`
+	Last  string `json:"last,omitempty" xml:"last,omitempty"`
+}
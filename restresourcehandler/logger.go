@@ -0,0 +1,63 @@
+package restresourcehandler
+
+// Logger is a minimal structured logger. Each method accepts a message followed by
+// alternating keys and values (e.g. logger.Debug("sending http request", "method", "GET")),
+// which makes *slog.Logger (log/slog) satisfy this interface.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// noopLogger is a Logger which discards all messages.
+type noopLogger struct{}
+
+func (noopLogger) Debug(string, ...interface{}) {}
+func (noopLogger) Info(string, ...interface{})  {}
+func (noopLogger) Warn(string, ...interface{})  {}
+func (noopLogger) Error(string, ...interface{}) {}
+
+// LogLevel is the minimal level of the messages logged by a RestResourceHandler (see Config.LogLevel).
+type LogLevel int
+
+const (
+	// LogLevelDebug logs all messages (e.g. every sent request and received response).
+	LogLevelDebug LogLevel = iota
+	// LogLevelInfo logs informational messages, warnings and errors.
+	LogLevelInfo
+	// LogLevelWarn logs warnings (e.g. retried requests) and errors.
+	LogLevelWarn
+	// LogLevelError logs errors only.
+	LogLevelError
+)
+
+// leveledLogger is a Logger discarding the messages below the given level.
+type leveledLogger struct {
+	logger Logger
+	level  LogLevel
+}
+
+func (l leveledLogger) Debug(msg string, keyvals ...interface{}) {
+	if l.level <= LogLevelDebug {
+		l.logger.Debug(msg, keyvals...)
+	}
+}
+
+func (l leveledLogger) Info(msg string, keyvals ...interface{}) {
+	if l.level <= LogLevelInfo {
+		l.logger.Info(msg, keyvals...)
+	}
+}
+
+func (l leveledLogger) Warn(msg string, keyvals ...interface{}) {
+	if l.level <= LogLevelWarn {
+		l.logger.Warn(msg, keyvals...)
+	}
+}
+
+func (l leveledLogger) Error(msg string, keyvals ...interface{}) {
+	if l.level <= LogLevelError {
+		l.logger.Error(msg, keyvals...)
+	}
+}
@@ -0,0 +1,84 @@
+package restresourcehandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Metrics receives measurements of the operations performed by RestResourceHandlers (see Config.Metrics).
+// Implementations must be safe for concurrent use. See InMemoryMetrics for a ready-made implementation.
+type Metrics interface {
+	// CountOperation is called once per finished operation (counter event).
+	CountOperation(labels MetricLabels)
+	// ObserveLatency is called once per finished operation with its duration (histogram event).
+	// The duration includes all retries of the operation and the interceptors (see Config.Interceptors).
+	ObserveLatency(labels MetricLabels, latency time.Duration)
+}
+
+// MetricLabels describe a finished operation (see Metrics).
+type MetricLabels struct {
+	// Resource is the name of the handled resource (see Config.ResourceName).
+	Resource string
+	// Method is the HTTP method of the operation ("GET", "DELETE", "POST" or "PATCH").
+	Method string
+	// StatusClass is the class of the final HTTP status of the operation (e.g. "2xx" or "5xx")
+	// or "none" if no response has been received.
+	StatusClass string
+	// ErrorKind is the kind of the error the operation failed with (one of the ErrorKind... constants)
+	// or empty if the operation succeeded.
+	ErrorKind string
+}
+
+// Kinds of errors reported in MetricLabels.ErrorKind.
+const (
+	ErrorKindRemote          = "remote"
+	ErrorKindTimeout         = "timeout"
+	ErrorKindCanceled        = "canceled"
+	ErrorKindCircuitOpen     = "circuit_open"
+	ErrorKindInvalidResponse = "invalid_response"
+	ErrorKindTransport       = "transport"
+	ErrorKindOther           = "other"
+)
+
+// noStatusClass is the status class of operations which received no response.
+const noStatusClass = "none"
+
+// statusClass returns the class of the given HTTP status (e.g. "4xx"), "none" for status 0.
+func statusClass(statusCode int) string {
+	if statusCode == 0 {
+		return noStatusClass
+	}
+
+	return fmt.Sprintf("%dxx", statusCode/100) //nolint:gomnd // HTTP status classes are hundreds
+}
+
+// errorKind classifies the error of an operation (empty for nil).
+func errorKind(err error) string {
+	var netErr net.Error
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrRemoteError):
+		return ErrorKindRemote
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorKindTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorKindCanceled
+	case errors.Is(err, ErrCircuitOpen):
+		return ErrorKindCircuitOpen
+	case errors.Is(err, ErrInvalidResponse), errors.Is(err, ErrResponseTooLarge):
+		return ErrorKindInvalidResponse
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return ErrorKindTimeout
+		}
+
+		return ErrorKindTransport
+	default:
+		return ErrorKindOther
+	}
+}
@@ -0,0 +1,40 @@
+package restresourcehandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	DescribeTable("classifies statuses",
+		func(statusCode int, expected string) {
+			Expect(statusClass(statusCode)).To(Equal(expected))
+		},
+		Entry("without response", 0, "none"),
+		Entry("success", http.StatusCreated, "2xx"),
+		Entry("client error", http.StatusNotFound, "4xx"),
+		Entry("server error", http.StatusBadGateway, "5xx"),
+	)
+
+	DescribeTable("classifies errors",
+		func(err error, expected string) {
+			Expect(errorKind(err)).To(Equal(expected))
+		},
+		Entry("no error", nil, ""),
+		Entry("remote error", &RemoteError{StatusCode: http.StatusNotFound}, ErrorKindRemote),
+		Entry("timeout", WrapError(context.DeadlineExceeded, "executing http request"), ErrorKindTimeout),
+		Entry("cancellation", context.Canceled, ErrorKindCanceled),
+		Entry("open circuit", &CircuitOpenError{}, ErrorKindCircuitOpen),
+		Entry("invalid response", InvalidResponseError("no data"), ErrorKindInvalidResponse),
+		Entry("too large response", &ResponseSizeError{Limit: 1}, ErrorKindInvalidResponse),
+		Entry("transport error",
+			&url.Error{Op: "Get", URL: "http://example.com", Err: errors.New("connection refused")},
+			ErrorKindTransport),
+		Entry("other error", errors.New("some error"), ErrorKindOther),
+	)
+})
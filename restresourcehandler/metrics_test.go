@@ -0,0 +1,130 @@
+package restresourcehandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InMemoryMetrics", func() {
+	var metrics *restresourcehandler.InMemoryMetrics
+
+	successfulGet := restresourcehandler.MetricLabels{
+		Resource:    "/api/people",
+		Method:      "GET",
+		StatusClass: "2xx",
+	}
+	failedPost := restresourcehandler.MetricLabels{
+		Resource:    "/api/people",
+		Method:      "POST",
+		StatusClass: "5xx",
+		ErrorKind:   restresourcehandler.ErrorKindRemote,
+	}
+
+	BeforeEach(func() {
+		metrics = restresourcehandler.NewInMemoryMetrics(time.Second, 100*time.Millisecond)
+
+		for _, latency := range []time.Duration{50 * time.Millisecond, 500 * time.Millisecond} {
+			metrics.CountOperation(successfulGet)
+			metrics.ObserveLatency(successfulGet, latency)
+		}
+
+		metrics.CountOperation(failedPost)
+		metrics.ObserveLatency(failedPost, 2*time.Second)
+	})
+
+	It("keeps statistics per labels", func() {
+		Expect(metrics.Buckets()).To(Equal([]time.Duration{100 * time.Millisecond, time.Second}))
+		Expect(metrics.Snapshot()).To(Equal([]restresourcehandler.OperationSeries{
+			{
+				Labels: successfulGet,
+				OperationStats: restresourcehandler.OperationStats{
+					Count:          2,
+					LatencyCount:   2,
+					LatencySum:     550 * time.Millisecond,
+					LatencyBuckets: []uint64{1, 2},
+				},
+			},
+			{
+				Labels: failedPost,
+				OperationStats: restresourcehandler.OperationStats{
+					Count:          1,
+					LatencyCount:   1,
+					LatencySum:     2 * time.Second,
+					LatencyBuckets: []uint64{0, 0},
+				},
+			},
+		}))
+	})
+
+	It("uses default buckets", func() {
+		Expect(restresourcehandler.NewInMemoryMetrics().Buckets()).To(Equal(restresourcehandler.DefaultLatencyBuckets))
+	})
+
+	It("exposes statistics in prometheus text format", func() {
+		var out strings.Builder
+
+		_, err := restresourcehandler.PrometheusExporter{Metrics: metrics, Namespace: "form3"}.WriteTo(&out)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal(`# HELP form3_operations_total Number of performed operations.
+# TYPE form3_operations_total counter
+form3_operations_total{resource="/api/people",method="GET",status_class="2xx",error_kind=""} 2
+form3_operations_total{resource="/api/people",method="POST",status_class="5xx",error_kind="remote"} 1
+# HELP form3_operation_duration_seconds Duration of performed operations in seconds.
+# TYPE form3_operation_duration_seconds histogram
+form3_operation_duration_seconds_bucket{resource="/api/people",method="GET",status_class="2xx",error_kind="",le="0.1"} 1
+form3_operation_duration_seconds_bucket{resource="/api/people",method="GET",status_class="2xx",error_kind="",le="1"} 2
+form3_operation_duration_seconds_bucket{resource="/api/people",method="GET",status_class="2xx",error_kind="",le="+Inf"} 2
+form3_operation_duration_seconds_sum{resource="/api/people",method="GET",status_class="2xx",error_kind=""} 0.55
+form3_operation_duration_seconds_count{resource="/api/people",method="GET",status_class="2xx",error_kind=""} 2
+form3_operation_duration_seconds_bucket{resource="/api/people",method="POST",status_class="5xx",error_kind="remote",le="0.1"} 0
+form3_operation_duration_seconds_bucket{resource="/api/people",method="POST",status_class="5xx",error_kind="remote",le="1"} 0
+form3_operation_duration_seconds_bucket{resource="/api/people",method="POST",status_class="5xx",error_kind="remote",le="+Inf"} 1
+form3_operation_duration_seconds_sum{resource="/api/people",method="POST",status_class="5xx",error_kind="remote"} 2
+form3_operation_duration_seconds_count{resource="/api/people",method="POST",status_class="5xx",error_kind="remote"} 1
+`))
+	})
+
+	It("keeps latency histogram consistent with its observations", func() {
+		metrics = restresourcehandler.NewInMemoryMetrics(time.Second)
+		metrics.CountOperation(successfulGet)
+
+		var out strings.Builder
+		_, err := restresourcehandler.PrometheusExporter{Metrics: metrics}.WriteTo(&out)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(ContainSubstring(`le="+Inf"} 0`))
+		Expect(out.String()).To(ContainSubstring(
+			`restresourcehandler_operation_duration_seconds_count{resource="/api/people",method="GET",` +
+				`status_class="2xx",error_kind=""} 0`))
+	})
+
+	It("escapes label values", func() {
+		metrics = restresourcehandler.NewInMemoryMetrics()
+		metrics.CountOperation(restresourcehandler.MetricLabels{Resource: "a\"b\\c\nd"})
+
+		var out strings.Builder
+		_, err := restresourcehandler.PrometheusExporter{Metrics: metrics}.WriteTo(&out)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(ContainSubstring(
+			`restresourcehandler_operations_total{resource="a\"b\\c\nd",method="",status_class="",error_kind=""} 1`))
+	})
+
+	It("serves statistics over http", func() {
+		recorder := httptest.NewRecorder()
+
+		restresourcehandler.PrometheusExporter{Metrics: metrics}.
+			ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("text/plain; version=0.0.4; charset=utf-8"))
+		Expect(recorder.Body.String()).To(HavePrefix("# HELP restresourcehandler_operations_total"))
+	})
+})
@@ -0,0 +1,95 @@
+package restresourcehandler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// prometheusContentType is the content type of the Prometheus text exposition format.
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PrometheusExporter exposes InMemoryMetrics in the Prometheus text exposition format:
+// a counter of operations ("<namespace>_operations_total") and a histogram of their latencies in seconds
+// ("<namespace>_operation_duration_seconds"), both labelled with "resource", "method", "status_class"
+// and "error_kind".
+//
+// PrometheusExporter is an http.Handler, so it can serve the scrape endpoint directly:
+//
+//	http.Handle("/metrics", restresourcehandler.PrometheusExporter{Metrics: metrics})
+type PrometheusExporter struct {
+	// Metrics are the exposed metrics.
+	Metrics *InMemoryMetrics
+	// Namespace is the prefix of the metric names (default "restresourcehandler").
+	Namespace string
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format. It implements io.WriterTo.
+func (e PrometheusExporter) WriteTo(writer io.Writer) (int64, error) {
+	namespace := e.Namespace
+	if namespace == "" {
+		namespace = "restresourcehandler"
+	}
+
+	counter := namespace + "_operations_total"
+	histogram := namespace + "_operation_duration_seconds"
+	series := e.Metrics.Snapshot()
+	buckets := e.Metrics.Buckets()
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "# HELP %s Number of performed operations.\n", counter)
+	fmt.Fprintf(&buf, "# TYPE %s counter\n", counter)
+
+	for _, s := range series {
+		fmt.Fprintf(&buf, "%s{%s} %d\n", counter, prometheusLabels(s.Labels), s.Count)
+	}
+
+	fmt.Fprintf(&buf, "# HELP %s Duration of performed operations in seconds.\n", histogram)
+	fmt.Fprintf(&buf, "# TYPE %s histogram\n", histogram)
+
+	for _, s := range series {
+		labels := prometheusLabels(s.Labels)
+
+		for i, bound := range buckets {
+			fmt.Fprintf(
+				&buf, "%s_bucket{%s,le=\"%s\"} %d\n", histogram, labels, formatFloat(bound.Seconds()), s.LatencyBuckets[i])
+		}
+
+		fmt.Fprintf(&buf, "%s_bucket{%s,le=\"+Inf\"} %d\n", histogram, labels, s.LatencyCount)
+		fmt.Fprintf(&buf, "%s_sum{%s} %s\n", histogram, labels, formatFloat(s.LatencySum.Seconds()))
+		fmt.Fprintf(&buf, "%s_count{%s} %d\n", histogram, labels, s.LatencyCount)
+	}
+
+	n, err := buf.WriteTo(writer)
+
+	return n, WrapError(err, "writing metrics")
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format. It implements http.Handler.
+func (e PrometheusExporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", prometheusContentType)
+	_, _ = e.WriteTo(w)
+}
+
+func prometheusLabels(labels MetricLabels) string {
+	return fmt.Sprintf(
+		`resource="%s",method="%s",status_class="%s",error_kind="%s"`,
+		escapeLabelValue(labels.Resource),
+		escapeLabelValue(labels.Method),
+		escapeLabelValue(labels.StatusClass),
+		escapeLabelValue(labels.ErrorKind))
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
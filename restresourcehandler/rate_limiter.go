@@ -0,0 +1,193 @@
+package restresourcehandler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting the rate of requests sent by RestResourceHandlers
+// (see Config.RateLimiter). A single RateLimiter can be shared by multiple handlers
+// (e.g. all resources of an API client) and is safe for concurrent use.
+// Use NewRateLimiter to construct instances of RateLimiter - a zero-valued RateLimiter
+// is rejected by New (see Config.RateLimiter).
+//
+// Apart from the configured rate, the limiter adapts to the rate limit state sent by the server:
+// the available tokens never exceed the "X-RateLimit-Remaining" header, all requests are held back
+// until "X-RateLimit-Reset" once no requests remain, and responses with HTTP status 429
+// hold back all requests for the duration given in the "Retry-After" header.
+type RateLimiter struct {
+	mu           sync.Mutex
+	rate         float64
+	burst        int
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+	waiting      int
+	now          func() time.Time
+}
+
+// RateLimiterState describes the current state of a RateLimiter (see RateLimiter.State).
+type RateLimiterState struct {
+	// Rate is the configured number of requests per second.
+	Rate float64
+	// Burst is the configured maximal number of requests sent at once.
+	Burst int
+	// Tokens is the number of requests which can be sent right away.
+	Tokens float64
+	// BlockedUntil is the time until which all requests are held back because of the server rate limit
+	// (zero if requests are not held back).
+	BlockedUntil time.Time
+	// Waiting is the number of requests currently waiting for the limiter.
+	Waiting int
+}
+
+// NewRateLimiter creates a RateLimiter allowing the given number of requests per second
+// with bursts of at most burst requests.
+// Returns an error wrapping ErrInvalidConfig (see ConfigError) if the rate or the burst is not positive.
+func NewRateLimiter(requestsPerSecond float64, burst int) (*RateLimiter, error) {
+	if requestsPerSecond <= 0 {
+		return nil, &ConfigError{"RateLimiter.Rate", "RateLimiter.Rate must be positive."}
+	}
+
+	if burst < 1 {
+		return nil, &ConfigError{"RateLimiter.Burst", "RateLimiter.Burst must be positive."}
+	}
+
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}, nil
+}
+
+// Wait blocks until a request can be sent or the context is done.
+// Returns the context error in the latter case.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.waiting++
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		l.waiting--
+		l.mu.Unlock()
+	}()
+
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// Observe adapts the limiter to the rate limit state sent by the server in the given response.
+// RestResourceHandler calls it for every received response.
+func (l *RateLimiter) Observe(resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock()
+	l.refill(now)
+
+	if rateLimit := parseRateLimit(resp.Header, now); rateLimit != nil && rateLimit.Remaining >= 0 {
+		if float64(rateLimit.Remaining) < l.tokens {
+			l.tokens = float64(rateLimit.Remaining)
+		}
+
+		if rateLimit.Remaining == 0 {
+			l.blockUntil(rateLimit.Reset)
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		l.tokens = 0
+
+		if delay, ok := retryAfter(resp.Header); ok {
+			l.blockUntil(now.Add(delay))
+		}
+	}
+}
+
+// State returns the current state of the limiter (e.g. for monitoring).
+func (l *RateLimiter) State() RateLimiterState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock()
+	l.refill(now)
+
+	state := RateLimiterState{
+		Rate:    l.rate,
+		Burst:   l.burst,
+		Tokens:  l.tokens,
+		Waiting: l.waiting,
+	}
+
+	if l.blockedUntil.After(now) {
+		state.BlockedUntil = l.blockedUntil
+	}
+
+	return state
+}
+
+// reserve takes a token if a request can be sent right away.
+// Otherwise returns the delay after which a token may be available.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock()
+	l.refill(now)
+
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil.Sub(now)
+	}
+
+	if l.tokens >= 1 {
+		l.tokens--
+
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (l *RateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * l.rate
+		if l.tokens > float64(l.burst) {
+			l.tokens = float64(l.burst)
+		}
+	}
+
+	l.last = now
+}
+
+// clock returns the current time (now is replaced in tests).
+func (l *RateLimiter) clock() time.Time {
+	if l.now == nil {
+		return time.Now()
+	}
+
+	return l.now()
+}
+
+// isValid reports whether the limiter has been constructed with NewRateLimiter.
+func (l *RateLimiter) isValid() bool {
+	return l.rate > 0 && l.burst > 0
+}
+
+func (l *RateLimiter) blockUntil(until time.Time) {
+	if until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+}
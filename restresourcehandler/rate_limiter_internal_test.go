@@ -0,0 +1,123 @@
+package restresourcehandler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RateLimiter", func() {
+	var limiter *RateLimiter
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Date(2022, 1, 25, 12, 0, 0, 0, time.UTC)
+
+		var err error
+		limiter, err = NewRateLimiter(10, 2)
+		Expect(err).NotTo(HaveOccurred())
+
+		limiter.now = func() time.Time { return now }
+		limiter.last = now
+	})
+
+	Context("fails construction", func() {
+		It("when rate is not positive", func() {
+			_, err := NewRateLimiter(0, 1)
+
+			Expect(err).To(MatchError(&ConfigError{"RateLimiter.Rate", "RateLimiter.Rate must be positive."}))
+		})
+
+		It("when burst is not positive", func() {
+			_, err := NewRateLimiter(1, 0)
+
+			Expect(err).To(MatchError(&ConfigError{"RateLimiter.Burst", "RateLimiter.Burst must be positive."}))
+		})
+	})
+
+	It("uses the current time if no clock has been set", func() {
+		limiter, err := NewRateLimiter(10, 2)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(limiter.reserve()).To(BeZero())
+		Expect((&RateLimiter{}).State()).To(Equal(RateLimiterState{}))
+	})
+
+	It("allows bursts and then limits the rate", func() {
+		Expect(limiter.reserve()).To(BeZero())
+		Expect(limiter.reserve()).To(BeZero())
+		Expect(limiter.reserve()).To(Equal(100 * time.Millisecond))
+
+		now = now.Add(50 * time.Millisecond)
+		Expect(limiter.reserve()).To(Equal(50 * time.Millisecond))
+
+		now = now.Add(50 * time.Millisecond)
+		Expect(limiter.reserve()).To(BeZero())
+	})
+
+	It("does not accumulate more tokens than the burst", func() {
+		now = now.Add(time.Hour)
+
+		Expect(limiter.State().Tokens).To(BeNumerically("==", 2))
+	})
+
+	It("limits tokens to the remaining requests sent by the server", func() {
+		limiter.Observe(responseWithStatus(http.StatusOK, http.Header{"X-Ratelimit-Remaining": []string{"1"}}))
+
+		Expect(limiter.reserve()).To(BeZero())
+		Expect(limiter.reserve()).To(BeNumerically(">", 0))
+	})
+
+	It("holds requests back until reset when no requests remain", func() {
+		reset := now.Add(30 * time.Second).Truncate(time.Second)
+
+		limiter.Observe(responseWithStatus(http.StatusOK, http.Header{
+			"X-Ratelimit-Remaining": []string{"0"},
+			"X-Ratelimit-Reset":     []string{strconv.FormatInt(reset.Unix(), 10)},
+		}))
+
+		Expect(limiter.reserve()).To(Equal(reset.Sub(now)))
+		Expect(limiter.State().BlockedUntil).To(BeTemporally("==", reset))
+
+		now = reset
+		Expect(limiter.reserve()).To(BeZero())
+		Expect(limiter.State().BlockedUntil).To(BeZero())
+	})
+
+	It("holds requests back according to Retry-After of rate limited responses", func() {
+		limiter.Observe(responseWithStatus(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"5"}}))
+
+		Expect(limiter.reserve()).To(Equal(5 * time.Second))
+		Expect(limiter.State()).To(Equal(RateLimiterState{
+			Rate:         10,
+			Burst:        2,
+			Tokens:       0,
+			BlockedUntil: now.Add(5 * time.Second),
+		}))
+	})
+
+	It("waits for a token", func() {
+		limiter.now = time.Now
+		limiter.last = time.Now()
+		limiter.tokens = 0
+
+		start := time.Now()
+		Expect(limiter.Wait(context.Background())).To(Succeed())
+
+		Expect(time.Since(start)).To(BeNumerically(">=", 90*time.Millisecond))
+		Expect(limiter.State().Waiting).To(BeZero())
+	})
+
+	It("stops waiting when context is done", func() {
+		limiter.blockedUntil = now.Add(time.Hour)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		Expect(limiter.Wait(ctx)).To(MatchError(context.DeadlineExceeded))
+	})
+})
@@ -0,0 +1,132 @@
+package restresourcehandler
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// RecordedSpan is a span captured by RecordingTracer.
+type RecordedSpan struct {
+	// Name is the name of the span.
+	Name string
+	// TraceContext is the trace context of the span.
+	TraceContext TraceContext
+	// ParentSpanID is the span id of the parent span (zero for root spans).
+	ParentSpanID [8]byte
+	// Attributes are the attributes set on the span.
+	Attributes map[string]interface{}
+	// Err is the recorded error (nil if none has been recorded).
+	Err error
+	// Start is the time the span has been started.
+	Start time.Time
+	// End is the time the span has been ended.
+	End time.Time
+}
+
+// RecordingTracer is a Tracer capturing all ended spans, e.g. to make assertions on them in tests.
+// All spans are sampled. Root spans start new traces with random ids
+// (and with the State of the tracer as their trace state).
+// Use NewRecordingTracer to construct instances of RecordingTracer.
+type RecordingTracer struct {
+	// State is the trace state of the root spans (see TraceContext.State).
+	State string
+
+	mu    sync.Mutex
+	spans []RecordedSpan
+}
+
+// NewRecordingTracer creates a RecordingTracer with no recorded spans.
+func NewRecordingTracer() *RecordingTracer {
+	return &RecordingTracer{}
+}
+
+// Start implements Tracer.
+func (t *RecordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordingSpan{
+		tracer: t,
+		recorded: RecordedSpan{
+			Name:       name,
+			Attributes: map[string]interface{}{},
+			Start:      time.Now(),
+		},
+	}
+
+	if parent, ok := ctx.Value(recordingSpanKey{}).(*recordingSpan); ok {
+		span.recorded.TraceContext = parent.TraceContext()
+		span.recorded.ParentSpanID = parent.recorded.TraceContext.SpanID
+	} else {
+		_, _ = rand.Read(span.recorded.TraceContext.TraceID[:])
+		span.recorded.TraceContext.Sampled = true
+		span.recorded.TraceContext.State = t.State
+	}
+
+	_, _ = rand.Read(span.recorded.TraceContext.SpanID[:])
+
+	return context.WithValue(ctx, recordingSpanKey{}, span), span
+}
+
+// Spans returns the ended spans in the order they have been ended.
+func (t *RecordingTracer) Spans() []RecordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]RecordedSpan(nil), t.spans...)
+}
+
+// Reset discards all recorded spans.
+func (t *RecordingTracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.spans = nil
+}
+
+// recordingSpanKey is the context key of the current recordingSpan.
+type recordingSpanKey struct{}
+
+// recordingSpan is a Span started by RecordingTracer.
+type recordingSpan struct {
+	tracer   *RecordingTracer
+	mu       sync.Mutex
+	recorded RecordedSpan
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recorded.Attributes[key] = value
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recorded.Err = err
+}
+
+func (s *recordingSpan) TraceContext() TraceContext {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.recorded.TraceContext
+}
+
+func (s *recordingSpan) End() {
+	s.mu.Lock()
+	s.recorded.End = time.Now()
+	recorded := s.recorded
+	recorded.Attributes = make(map[string]interface{}, len(s.recorded.Attributes))
+
+	for key, value := range s.recorded.Attributes {
+		recorded.Attributes[key] = value
+	}
+	s.mu.Unlock()
+
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.tracer.spans = append(s.tracer.spans, recorded)
+}
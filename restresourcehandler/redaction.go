@@ -0,0 +1,169 @@
+package restresourcehandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// Redactor masks sensitive values in logged data (see Config.Redactor).
+type Redactor interface {
+	// RedactBody returns a copy of the given request or response body with the sensitive values masked.
+	RedactBody(body []byte) []byte
+	// RedactURL returns the given URL with the sensitive query parameter values masked.
+	RedactURL(u *url.URL) string
+}
+
+// FieldRedactor is a Redactor masking the values of the given fields: the properties of JSON bodies
+// (at any depth) and the query parameters (e.g. both "iban" and "filter[iban]" for the "iban" field).
+// Masked values keep only their last 4 characters (e.g. "GB11NWBK40030041426819" becomes "******************6819"),
+// shorter values are masked entirely. String arrays are masked element by element and comma-separated
+// query parameter values are masked value by value.
+// Bodies which are not valid JSON are replaced entirely, as they cannot be redacted selectively.
+type FieldRedactor struct {
+	// Fields are the names of the masked fields (e.g. "iban").
+	Fields []string
+}
+
+// redactedBody replaces the bodies which cannot be redacted.
+const redactedBody = "[REDACTED]"
+
+// unmaskedSuffixLength is the number of trailing characters left visible by masking.
+const unmaskedSuffixLength = 4
+
+// RedactBody implements Redactor.
+func (r FieldRedactor) RedactBody(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return []byte(redactedBody)
+	}
+
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(r.redactProperties(value)); err != nil {
+		return []byte(redactedBody)
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// RedactURL implements Redactor.
+func (r FieldRedactor) RedactURL(u *url.URL) string {
+	query := u.Query()
+	isRedacted := false
+
+	for key, values := range query {
+		if !r.isSensitiveParam(key) {
+			continue
+		}
+
+		for i, value := range values {
+			items := strings.Split(value, ",")
+			for j, item := range items {
+				items[j] = mask(item)
+			}
+
+			values[i] = strings.Join(items, ",")
+		}
+
+		isRedacted = true
+	}
+
+	if !isRedacted {
+		return u.String()
+	}
+
+	redacted := *u
+	redacted.RawQuery = query.Encode()
+
+	return redacted.String()
+}
+
+// redactProperties masks the sensitive properties of a decoded JSON value.
+func (r FieldRedactor) redactProperties(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, property := range value {
+			if r.isSensitive(key) {
+				value[key] = maskValue(property)
+			} else {
+				value[key] = r.redactProperties(property)
+			}
+		}
+	case []interface{}:
+		for i, element := range value {
+			value[i] = r.redactProperties(element)
+		}
+	}
+
+	return value
+}
+
+func (r FieldRedactor) isSensitive(name string) bool {
+	for _, field := range r.Fields {
+		if name == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r FieldRedactor) isSensitiveParam(key string) bool {
+	for _, field := range r.Fields {
+		if key == field || strings.HasSuffix(key, "["+field+"]") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maskValue masks a decoded JSON value (strings, numbers and arrays of them).
+func maskValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		return mask(value)
+	case json.Number:
+		return mask(value.String())
+	case []interface{}:
+		masked := make([]interface{}, len(value))
+		for i, element := range value {
+			masked[i] = maskValue(element)
+		}
+
+		return masked
+	case nil, bool:
+		return value
+	default:
+		return redactedBody
+	}
+}
+
+// mask replaces all but the last 4 characters of the value with asterisks.
+// Values not longer than 4 characters are masked entirely.
+func mask(value string) string {
+	runes := []rune(value)
+	if len(runes) <= unmaskedSuffixLength {
+		return strings.Repeat("*", len(runes))
+	}
+
+	return strings.Repeat("*", len(runes)-unmaskedSuffixLength) + string(runes[len(runes)-unmaskedSuffixLength:])
+}
+
+// noopRedactor is a Redactor which does not mask anything.
+type noopRedactor struct{}
+
+func (noopRedactor) RedactBody(body []byte) []byte { return body }
+func (noopRedactor) RedactURL(u *url.URL) string   { return u.String() }
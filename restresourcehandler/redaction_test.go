@@ -0,0 +1,55 @@
+package restresourcehandler_test
+
+import (
+	"net/url"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FieldRedactor", func() {
+	redactor := restresourcehandler.FieldRedactor{Fields: []string{"iban", "name", "account_number"}}
+
+	DescribeTable("redacts body",
+		func(body string, expectedBody string) {
+			Expect(string(redactor.RedactBody([]byte(body)))).To(Equal(expectedBody))
+		},
+		Entry("empty body", "", ""),
+		Entry("no sensitive fields", `{"country":"GB","bic":"NWBKGB22"}`, `{"bic":"NWBKGB22","country":"GB"}`),
+		Entry("string keeping last 4 characters",
+			`{"iban":"GB11NWBK40030041426819"}`, `{"iban":"******************6819"}`),
+		Entry("short string entirely", `{"iban":"1234"}`, `{"iban":"****"}`),
+		Entry("multi-byte characters", `{"iban":"ŻÓŁĆŃ123"}`, `{"iban":"****Ń123"}`),
+		Entry("number", `{"account_number":41426819}`, `{"account_number":"****6819"}`),
+		Entry("string array element by element",
+			`{"name":["Jane Doe","Mr Smith"]}`, `{"name":["**** Doe","****mith"]}`),
+		Entry("nested properties",
+			`{"data":{"attributes":{"iban":"GB11NWBK40030041426819","country":"GB"}}}`,
+			`{"data":{"attributes":{"country":"GB","iban":"******************6819"}}}`),
+		Entry("properties in arrays",
+			`{"data":[{"name":["Jane Doe"]},{"name":["Mr Smith"]}]}`,
+			`{"data":[{"name":["**** Doe"]},{"name":["****mith"]}]}`),
+		Entry("null", `{"iban":null}`, `{"iban":null}`),
+		Entry("object entirely", `{"name":{"first":"Jane"}}`, `{"name":"[REDACTED]"}`),
+		Entry("non-JSON body entirely", `<iban>GB11NWBK40030041426819</iban>`, `[REDACTED]`),
+		Entry("multiple JSON values entirely", `{"iban":"1234"} {"iban":"5678"}`, `[REDACTED]`),
+	)
+
+	DescribeTable("redacts url",
+		func(rawURL string, expectedURL string) {
+			u, err := url.Parse(rawURL)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(redactor.RedactURL(u)).To(Equal(expectedURL))
+		},
+		Entry("no query", "http://host/accounts/1", "http://host/accounts/1"),
+		Entry("no sensitive parameters",
+			"http://host/accounts?filter%5Bcountry%5D=GB", "http://host/accounts?filter%5Bcountry%5D=GB"),
+		Entry("parameter", "http://host/accounts?iban=GB11NWBK40030041426819",
+			"http://host/accounts?iban=%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A%2A6819"),
+		Entry("filter parameter with comma-separated values",
+			"http://host/accounts?filter%5Baccount_number%5D=41426819,41426820&filter%5Bcountry%5D=GB",
+			"http://host/accounts?filter%5Baccount_number%5D=%2A%2A%2A%2A6819%2C%2A%2A%2A%2A6820&filter%5Bcountry%5D=GB"),
+	)
+})
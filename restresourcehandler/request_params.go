@@ -7,7 +7,8 @@ import (
 
 // requestParams represents parameters of a REST API endpoint call.
 type requestParams struct {
-	// HTTPMethod is "GET" (fetch resource), "DELETE" (delete resource) or "POST" (resource creation).
+	// HTTPMethod is "GET" (fetch resource), "DELETE" (delete resource), "POST" (resource creation)
+	// or "PATCH" (resource update).
 	HTTPMethod string
 	// ExpectedStatus is the HTTP status which will be considered a success.
 	ExpectedStatus int
@@ -20,25 +21,35 @@ type requestParams struct {
 	ResourceID string
 	// QueryParams are additional query params to send with this request.
 	QueryParams map[string]string
+	// Header contains additional headers to send with this request (overriding Config.Header).
+	Header http.Header
+	// IdempotencyKey is an optional key sent in the Idempotency-Key header,
+	// which allows the server to recognize retries of the same POST request.
+	IdempotencyKey string
 	// Resource is an object to be JSON-serialized and sent in this request.
 	Resource interface{}
 	// Response is an object that will be filled with the JSON-deserialized response content.
 	Response interface{}
+	// Metadata is an optional object that will be filled with the response metadata
+	// (including the links and meta sent along the response content).
+	Metadata *ResponseMetadata
 }
 
 // validateRequestParameters does a sanity check of a requestParams instance.
-func validateRequestParameters(params requestParams) {
+func validateRequestParameters(params requestParams) error {
 	if !params.DoDiscardResourceID && params.ResourceID == "" {
-		panic("Invalid request parameters: ResourceID is empty, but DoDiscardResourceID is not set.")
+		return RequestParamsError("ResourceID is empty, but DoDiscardResourceID is not set.")
 	}
 
 	if !params.DoDiscardContent && params.Response == nil {
-		panic("Invalid request parameters: Response is null, but DoDiscardContent is not set.")
+		return RequestParamsError("Response is null, but DoDiscardContent is not set.")
 	}
 
 	switch params.HTTPMethod {
-	case http.MethodGet, http.MethodDelete, http.MethodPost:
+	case http.MethodGet, http.MethodDelete, http.MethodPost, http.MethodPatch:
 	default:
-		panic(fmt.Sprintf(`Unknown HTTP method "%s".`, params.HTTPMethod))
+		return RequestParamsError(fmt.Sprintf(`Unknown HTTP method "%s".`, params.HTTPMethod))
 	}
+
+	return nil
 }
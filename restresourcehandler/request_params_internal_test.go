@@ -14,12 +14,16 @@ func someValidRequestParams() requestParams {
 }
 
 var _ = Describe("requestParams", func() {
-	Context("panics during validation", func() {
+	It("passes validation", func() {
+		Expect(validateRequestParameters(someValidRequestParams())).To(Succeed())
+	})
+
+	Context("fails validation", func() {
 		It("when invalid http method has not been set", func() {
 			params := someValidRequestParams()
 			params.HTTPMethod = "UNKNOWN_METHOD"
 
-			Expect(func() { validateRequestParameters(params) }).To(PanicWith(`Unknown HTTP method "UNKNOWN_METHOD".`))
+			Expect(validateRequestParameters(params)).To(MatchError(RequestParamsError(`Unknown HTTP method "UNKNOWN_METHOD".`)))
 		})
 
 		It("when resource id has not been set and it shall not be discarded", func() {
@@ -27,8 +31,8 @@ var _ = Describe("requestParams", func() {
 			params.DoDiscardResourceID = false
 			params.ResourceID = ""
 
-			Expect(func() { validateRequestParameters(params) }).
-				To(PanicWith("Invalid request parameters: ResourceID is empty, but DoDiscardResourceID is not set."))
+			Expect(validateRequestParameters(params)).
+				To(MatchError(RequestParamsError("ResourceID is empty, but DoDiscardResourceID is not set.")))
 		})
 
 		It("when response content placeholder has not been set and it shall not be discarded", func() {
@@ -36,8 +40,8 @@ var _ = Describe("requestParams", func() {
 			params.DoDiscardContent = false
 			params.Response = nil
 
-			Expect(func() { validateRequestParameters(params) }).
-				To(PanicWith("Invalid request parameters: Response is null, but DoDiscardContent is not set."))
+			Expect(validateRequestParameters(params)).
+				To(MatchError(RequestParamsError("Response is null, but DoDiscardContent is not set.")))
 		})
 	})
 })
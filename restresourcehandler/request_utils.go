@@ -3,46 +3,171 @@ package restresourcehandler
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
 	"path"
+	"time"
 )
 
 func defaultRemoteErrorExtractor(response *http.Response) error {
-	return RemoteError(response.StatusCode)
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, MaxRemoteErrorBodyLength))
+	if err != nil {
+		return WrapError(err, "reading error response")
+	}
+
+	return NewRemoteError(response, body)
 }
 
 func (c *RestResourceHandler) request(ctx context.Context, params requestParams) error {
-	validateRequestParameters(params)
+	if err := validateRequestParameters(params); err != nil {
+		return err
+	}
+
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+
+		defer cancel()
+	}
+
+	op := Operation{
+		Method:         params.HTTPMethod,
+		ResourceURL:    c.resourceURL.String(),
+		ResourceID:     params.ResourceID,
+		QueryParams:    copyQueryParams(params.QueryParams),
+		Resource:       params.Resource,
+		Response:       params.Response,
+		IdempotencyKey: params.IdempotencyKey,
+		Header:         http.Header{},
+	}
+
+	if params.HTTPMethod == http.MethodPost && op.IdempotencyKey == "" && c.config.IdempotencyKey != nil {
+		key, err := c.config.IdempotencyKey(params.Resource)
+		if err != nil {
+			return err
+		}
+
+		op.IdempotencyKey = key
+	}
+
+	invoke := chainInterceptors(c.config.Interceptors, func(ctx context.Context, op *Operation) error {
+		params.ResourceID = op.ResourceID
+		params.QueryParams = op.QueryParams
+		params.Resource = op.Resource
+		params.Response = op.Response
+		params.Header = op.Header
+		params.IdempotencyKey = op.IdempotencyKey
+
+		return c.perform(ctx, params)
+	})
+
+	if params.Metadata == nil {
+		params.Metadata = &ResponseMetadata{}
+	}
+
+	ctx, span := c.config.tracer().Start(ctx, c.resourceName()+" "+params.HTTPMethod)
+	defer span.End()
+
+	start := time.Now()
+	err := invoke(ctx, &op)
+
+	c.trace(span, params, err)
+
+	if c.config.Metrics != nil {
+		c.observe(params, err, time.Since(start))
+	}
+
+	return err
+}
+
+// copyQueryParams copies the given query params, so that interceptors can modify them
+// without affecting the map passed in by the caller.
+func copyQueryParams(queryParams map[string]string) map[string]string {
+	queryParamsCopy := make(map[string]string, len(queryParams))
+	for key, value := range queryParams {
+		queryParamsCopy[key] = value
+	}
+
+	return queryParamsCopy
+}
+
+// resourceName returns the name of the handled resource (see Config.ResourceName).
+func (c *RestResourceHandler) resourceName() string {
+	if c.config.ResourceName == "" {
+		return c.resourceURL.Path
+	}
+
+	return c.config.ResourceName
+}
+
+// trace sets the attributes of the span of a finished operation.
+func (c *RestResourceHandler) trace(span Span, params requestParams, err error) {
+	span.SetAttribute(AttributeResource, c.resourceName())
+	span.SetAttribute(AttributeHTTPMethod, params.HTTPMethod)
 
-	var id *string
 	if !params.DoDiscardResourceID {
-		id = &params.ResourceID
+		span.SetAttribute(AttributeResourceID, params.ResourceID)
 	}
 
-	req, err := createRequest(ctx, c.config, c.resourceURL, params.HTTPMethod, id, params.QueryParams, params.Resource)
-	if err != nil {
-		return err
+	if params.Metadata.StatusCode != 0 {
+		span.SetAttribute(AttributeHTTPStatusCode, params.Metadata.StatusCode)
 	}
 
-	if !params.DoDiscardContent {
-		req.Header.Add("Accept", c.config.ResourceEncoding)
+	if params.Metadata.Attempts != 0 {
+		span.SetAttribute(AttributeRetryAttempt, params.Metadata.Attempts)
 	}
 
-	if params.Resource != nil {
-		req.Header.Add("Content-Type", c.config.ResourceEncoding)
+	if err != nil {
+		span.RecordError(err)
 	}
+}
 
-	resp, err := c.client.Do(req)
+// observe reports a finished operation to Config.Metrics.
+func (c *RestResourceHandler) observe(params requestParams, err error, latency time.Duration) {
+	labels := MetricLabels{
+		Resource:    c.resourceName(),
+		Method:      params.HTTPMethod,
+		StatusClass: statusClass(params.Metadata.StatusCode),
+		ErrorKind:   errorKind(err),
+	}
+
+	c.config.Metrics.CountOperation(labels)
+	c.config.Metrics.ObserveLatency(labels, latency)
+}
+
+// perform executes the request described by params and reads the response.
+func (c *RestResourceHandler) perform(ctx context.Context, params requestParams) error {
+	start := time.Now()
+
+	resp, attempts, err := c.execute(ctx, params)
 	if err != nil {
-		return WrapError(err, "executing http request")
+		if params.Metadata != nil {
+			params.Metadata.Latency = time.Since(start)
+			params.Metadata.Attempts = attempts
+		}
+
+		return err
 	}
 	defer resp.Body.Close()
 
+	if params.Metadata != nil {
+		*params.Metadata = newResponseMetadata(resp, time.Now())
+		params.Metadata.Latency = time.Since(start)
+		params.Metadata.Attempts = attempts
+	}
+
 	if resp.StatusCode != params.ExpectedStatus {
+		// error responses are read by the extractors up to MaxRemoteErrorBodyLength,
+		// so MaxResponseSize does not apply to them
+		if c.config.isBodyLogged() {
+			c.logResponseBody(resp, MaxRemoteErrorBodyLength)
+		}
+
 		if c.config.RemoteErrorExtractor == nil {
 			return defaultRemoteErrorExtractor(resp)
 		}
@@ -54,7 +179,315 @@ func (c *RestResourceHandler) request(ctx context.Context, params requestParams)
 		return nil
 	}
 
-	return readResponse(c.config, resp.Body, params.Response)
+	if c.config.MaxResponseSize > 0 {
+		if resp.ContentLength > c.config.MaxResponseSize {
+			return &ResponseSizeError{c.config.MaxResponseSize}
+		}
+
+		resp.Body = &sizeLimitedReader{resp.Body, c.config.MaxResponseSize, c.config.MaxResponseSize}
+	}
+
+	if c.config.isBodyLogged() {
+		c.logResponseBody(resp, math.MaxInt64)
+	}
+
+	codec := c.config.responseCodec(resp.Header.Get("Content-Type"))
+
+	return readResponse(c.config, codec, resp.Body, params.Response, params.Metadata)
+}
+
+// execute sends the HTTP request described by params, retrying it according to Config.RetryPolicy.
+// The request (including its body) is constructed anew for every attempt.
+// Returns the final response and the number of attempts made.
+func (c *RestResourceHandler) execute(ctx context.Context, params requestParams) (*http.Response, int, error) {
+	for attempt := 1; ; attempt++ {
+		req, resp, err := c.send(ctx, params, attempt)
+		if req == nil {
+			// the request could not be constructed - retrying would not help
+			return nil, attempt, err
+		}
+
+		delay, doRetry := c.config.RetryPolicy.retryDelay(ctx, params, attempt, resp, err)
+		if !doRetry {
+			if err != nil {
+				return nil, attempt, WrapError(err, "executing http request")
+			}
+
+			return resp, attempt, nil
+		}
+
+		c.config.logger().Warn(
+			"retrying http request",
+			"method", params.HTTPMethod,
+			"url", c.config.redactor().RedactURL(req.URL),
+			"attempt", attempt,
+			"delay", delay)
+
+		if resp != nil {
+			discardResponse(resp)
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, attempt, WrapError(err, "waiting to retry http request")
+		}
+	}
+}
+
+// send sends a single attempt of the HTTP request described by params.
+// If the request is rejected with HTTP status 401, the credentials of Config.AuthProvider
+// are invalidated and the request is sent once more.
+func (c *RestResourceHandler) send(
+	ctx context.Context,
+	params requestParams,
+	attempt int) (*http.Request, *http.Response, error) {
+	logger := c.config.logger()
+	redactor := c.config.redactor()
+
+	for isReauthenticated := false; ; isReauthenticated = true {
+		req, err := c.newHTTPRequest(ctx, params)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if c.config.RateLimiter != nil {
+			if err := c.config.RateLimiter.Wait(ctx); err != nil {
+				return req, nil, WrapError(err, "waiting for rate limiter")
+			}
+		}
+
+		if c.config.AuthProvider != nil {
+			if err := c.config.AuthProvider.Authenticate(req); err != nil {
+				return req, nil, WrapError(err, "authenticating request")
+			}
+		}
+
+		redactedURL := redactor.RedactURL(req.URL)
+		keyvals := []interface{}{"method", req.Method, "url", redactedURL, "attempt", attempt}
+
+		if c.config.isBodyLogged() && req.GetBody != nil {
+			keyvals = append(keyvals, "body", string(redactor.RedactBody(requestBody(req))))
+		}
+
+		logger.Debug("sending http request", keyvals...)
+
+		start := time.Now()
+		resp, err := c.do(req, attempt)
+
+		if err != nil {
+			logger.Debug("http request failed", "method", req.Method, "url", redactedURL, "error", err)
+
+			return req, nil, err //nolint:wrapcheck // the caller wraps this error
+		}
+
+		if c.config.RateLimiter != nil {
+			c.config.RateLimiter.Observe(resp)
+		}
+
+		logger.Debug(
+			"received http response",
+			"method", req.Method,
+			"url", redactedURL,
+			"status", resp.StatusCode,
+			"duration", time.Since(start))
+
+		if resp.StatusCode != http.StatusUnauthorized || c.config.AuthProvider == nil || isReauthenticated {
+			return req, resp, nil
+		}
+
+		c.config.AuthProvider.Invalidate(req)
+		discardResponse(resp)
+	}
+}
+
+// do sends the given request attempt within a span (see Config.Tracer)
+// propagating the trace context of the span to the server.
+func (c *RestResourceHandler) do(req *http.Request, attempt int) (*http.Response, error) {
+	_, span := c.config.tracer().Start(req.Context(), "HTTP "+req.Method)
+	defer span.End()
+
+	span.SetAttribute(AttributeHTTPMethod, req.Method)
+	span.SetAttribute(AttributeHTTPURL, c.config.redactor().RedactURL(req.URL))
+	span.SetAttribute(AttributeRetryAttempt, attempt)
+	injectTraceContext(req.Header, span.TraceContext())
+
+	resp, err := c.doWithCircuitBreaker(req)
+	if err != nil {
+		err = redactURLError(err, c.config.redactor(), req.URL)
+		span.RecordError(err)
+
+		return nil, err
+	}
+
+	span.SetAttribute(AttributeHTTPStatusCode, resp.StatusCode)
+
+	return resp, nil
+}
+
+// redactURLError replaces the URL of the *url.Error returned by http.Client with its redacted version,
+// so that the error can be logged and traced.
+func redactURLError(err error, redactor Redactor, requestURL *url.URL) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+
+	redacted := *urlErr
+	redacted.URL = redactor.RedactURL(requestURL)
+
+	return &redacted
+}
+
+// doWithCircuitBreaker sends the given request, unless it is rejected by Config.CircuitBreaker.
+func (c *RestResourceHandler) doWithCircuitBreaker(req *http.Request) (*http.Response, error) {
+	breaker := c.config.CircuitBreaker
+	if breaker == nil {
+		return c.client.Do(req) //nolint:wrapcheck // the caller wraps this error
+	}
+
+	generation, err := breaker.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	breaker.record(req.Context(), generation, resp, err)
+
+	return resp, err //nolint:wrapcheck // the caller wraps this error
+}
+
+func (c *RestResourceHandler) newHTTPRequest(ctx context.Context, params requestParams) (*http.Request, error) {
+	var id *string
+	if !params.DoDiscardResourceID {
+		id = &params.ResourceID
+	}
+
+	req, err := createRequest(ctx, c.config, c.resourceURL, params.HTTPMethod, id, params.QueryParams, params.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range c.config.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	for key, values := range params.Header {
+		req.Header.Del(key)
+
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	if !params.DoDiscardContent {
+		req.Header.Add("Accept", c.config.accept())
+	}
+
+	if params.Resource != nil {
+		req.Header.Add("Content-Type", c.config.codec().ContentType())
+	}
+
+	if params.IdempotencyKey != "" {
+		req.Header.Add("Idempotency-Key", params.IdempotencyKey)
+	}
+
+	return req, nil
+}
+
+// sizeLimitedReader is a response body which fails with ResponseSizeError
+// if more than limit bytes are read from it.
+type sizeLimitedReader struct {
+	body      io.ReadCloser
+	limit     int64
+	remaining int64
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		// check if the body ends exactly at the limit
+		var probe [1]byte
+
+		n, err := r.body.Read(probe[:])
+		if n > 0 {
+			return 0, &ResponseSizeError{r.limit}
+		}
+
+		return 0, err //nolint:wrapcheck // io.EOF must not be wrapped
+	}
+
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+
+	n, err := r.body.Read(p)
+	r.remaining -= int64(n)
+
+	return n, err //nolint:wrapcheck // io.EOF must not be wrapped
+}
+
+func (r *sizeLimitedReader) Close() error {
+	return r.body.Close() //nolint:wrapcheck // the caller wraps this error
+}
+
+// requestBody returns a copy of the body of the given request (nil if it cannot be read).
+func requestBody(req *http.Request) []byte {
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+
+	return content
+}
+
+// logResponseBody logs the (redacted) body of the given response (at most limit bytes of it).
+// The logged part of the body is replaced with a buffered copy, so that the body can still be read in full.
+func (c *RestResourceHandler) logResponseBody(resp *http.Response, limit int64) {
+	content, err := ioutil.ReadAll(io.LimitReader(resp.Body, limit))
+
+	var reader io.Reader = bytes.NewReader(content)
+	if err != nil {
+		// the read error is reported when the response is decoded
+		reader = io.MultiReader(reader, errorReader{err})
+	} else {
+		reader = io.MultiReader(reader, resp.Body)
+	}
+
+	resp.Body = bufferedBody{reader, resp.Body}
+
+	c.config.logger().Debug(
+		"received http response body",
+		"method", resp.Request.Method,
+		"url", c.config.redactor().RedactURL(resp.Request.URL),
+		"status", resp.StatusCode,
+		"body", string(c.config.redactor().RedactBody(content)))
+}
+
+// bufferedBody is a response body read in advance (see logResponseBody).
+type bufferedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// errorReader is an io.Reader failing with the given error.
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// discardResponse reads the rest of the response body (so that the connection can be reused) and closes it.
+func discardResponse(resp *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
 }
 
 func createRequest(
@@ -98,42 +531,60 @@ func createRequest(
 	return req, nil
 }
 
-func readResponse(config Config, reader io.Reader, response interface{}) error {
-	respPayload, err := ioutil.ReadAll(reader)
-	if err != nil {
-		return WrapError(err, "decoding response")
+// readResponse decodes the response read from reader into response
+// (and the links and meta sent along, if metadata is not nil).
+// The response is decoded in a streaming manner (see Codec.Unwrap).
+func readResponse(
+	config Config,
+	codec Codec,
+	reader io.Reader,
+	response interface{},
+	metadata *ResponseMetadata) error {
+	if !config.IsDataWrapped {
+		err := codec.Decode(reader, response)
+
+		return WrapError(err, "parsing response")
 	}
 
-	if !config.IsDataWrapped {
-		err = json.Unmarshal(respPayload, &response)
+	properties := map[string]interface{}{config.DataPropertyName: response}
 
-		return WrapError(err, "parsing response json")
+	if metadata != nil && config.LinksPropertyName != "" {
+		properties[config.LinksPropertyName] = &metadata.Links
 	}
 
-	var responseMap map[string]json.RawMessage
-	err = json.Unmarshal(respPayload, &responseMap)
+	if metadata != nil && config.MetaPropertyName != "" {
+		properties[config.MetaPropertyName] = &metadata.Meta
+	}
 
+	missing, err := codec.Unwrap(reader, properties)
 	if err != nil {
-		return WrapError(err, "parsing response json")
+		return WrapError(err, "parsing response")
 	}
 
-	err = json.Unmarshal(responseMap[config.DataPropertyName], &response)
+	for _, property := range missing {
+		if property == config.DataPropertyName {
+			return InvalidResponseError(fmt.Sprintf(`response contains no "%s" property`, property))
+		}
+	}
 
-	return WrapError(err, "parsing response json")
+	return nil
 }
 
 func readerForResource(config Config, resource interface{}) (io.Reader, error) {
-	payload, err := json.Marshal(resource)
-	if err != nil {
-		return nil, WrapError(err, "marshalling request json")
-	}
+	var (
+		codec   = config.codec()
+		payload []byte
+		err     error
+	)
 
 	if config.IsDataWrapped {
-		payload, err = json.Marshal(map[string]json.RawMessage{"data": payload})
+		payload, err = codec.Wrap(config.DataPropertyName, resource)
+	} else {
+		payload, err = codec.Marshal(resource)
 	}
 
 	if err != nil {
-		return nil, WrapError(err, "marshalling request json")
+		return nil, WrapError(err, "encoding request")
 	}
 
 	return bytes.NewReader(payload), nil
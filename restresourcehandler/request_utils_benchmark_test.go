@@ -0,0 +1,114 @@
+package restresourcehandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+type benchmarkResource struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	Version    int64             `json:"version"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// benchmarkPage returns an encoded list page of n resources.
+func benchmarkPage(n int) []byte {
+	resources := make([]benchmarkResource, n)
+	for i := range resources {
+		resources[i] = benchmarkResource{
+			ID:      fmt.Sprintf("ad27e265-9605-4b4b-a0e5-%012d", i),
+			Type:    "accounts",
+			Version: int64(i),
+			Attributes: map[string]string{
+				"country":        "GB",
+				"bank_id":        "400300",
+				"account_number": fmt.Sprintf("%08d", i),
+				"iban":           fmt.Sprintf("GB11NWBK400300%08d", i),
+			},
+		}
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"data":  resources,
+		"links": Links{Self: "/v1/organisation/accounts?page[number]=0"},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return payload
+}
+
+// readResponseBuffered is the former (non-streaming) implementation of readResponse,
+// kept as the baseline of the benchmarks.
+func readResponseBuffered(config Config, body *bytes.Reader, response interface{}, links *Links) error {
+	payload, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err //nolint:wrapcheck // benchmark code
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return err //nolint:wrapcheck // benchmark code
+	}
+
+	if err := json.Unmarshal(envelope[config.DataPropertyName], response); err != nil {
+		return err //nolint:wrapcheck // benchmark code
+	}
+
+	return json.Unmarshal(envelope[config.LinksPropertyName], links) //nolint:wrapcheck // benchmark code
+}
+
+func benchmarkReadResponse(b *testing.B, pageSize int, read func(Config, *bytes.Reader, interface{}, *Links) error) {
+	b.Helper()
+
+	config := Config{
+		ResourceEncoding:  "application/json",
+		IsDataWrapped:     true,
+		DataPropertyName:  "data",
+		LinksPropertyName: "links",
+	}
+	payload := benchmarkPage(pageSize)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(payload)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var response []benchmarkResource
+
+		var links Links
+
+		if err := read(config, bytes.NewReader(payload), &response, &links); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func readResponseStreaming(config Config, body *bytes.Reader, response interface{}, links *Links) error {
+	var metadata ResponseMetadata
+	err := readResponse(config, config.codec(), body, response, &metadata)
+	*links = metadata.Links
+
+	return err
+}
+
+func BenchmarkReadResponseStreaming10(b *testing.B) {
+	benchmarkReadResponse(b, 10, readResponseStreaming)
+}
+
+func BenchmarkReadResponseBuffered10(b *testing.B) {
+	benchmarkReadResponse(b, 10, readResponseBuffered)
+}
+
+func BenchmarkReadResponseStreaming1000(b *testing.B) {
+	benchmarkReadResponse(b, 1000, readResponseStreaming)
+}
+
+func BenchmarkReadResponseBuffered1000(b *testing.B) {
+	benchmarkReadResponse(b, 1000, readResponseBuffered)
+}
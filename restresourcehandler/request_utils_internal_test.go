@@ -0,0 +1,62 @@
+package restresourcehandler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+type someResource struct {
+	Name string `json:"name"`
+}
+
+var _ = Describe("request", func() {
+	var server *ghttp.Server
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("retries POST request with idempotency key sending the same body", func() {
+		handler := MustNew(
+			&http.Client{},
+			server.URL()+"/resources",
+			Config{
+				ResourceEncoding: "application/json",
+				RetryPolicy:      &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+			})
+		attempt := ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", "/resources"),
+			ghttp.VerifyHeaderKV("Idempotency-Key", "some-key"),
+			ghttp.VerifyJSONRepresenting(someResource{"Smith"}))
+
+		server.AppendHandlers(
+			ghttp.CombineHandlers(attempt, ghttp.RespondWith(http.StatusServiceUnavailable, nil)),
+			ghttp.CombineHandlers(attempt, ghttp.RespondWith(http.StatusBadGateway, nil)),
+			ghttp.CombineHandlers(attempt, ghttp.RespondWithJSONEncoded(http.StatusCreated, someResource{"Smith"})))
+
+		var response someResource
+		err := handler.request(
+			context.Background(),
+			requestParams{
+				HTTPMethod:          http.MethodPost,
+				DoDiscardResourceID: true,
+				IdempotencyKey:      "some-key",
+				Resource:            someResource{"Smith"},
+				Response:            &response,
+				ExpectedStatus:      http.StatusCreated,
+			})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response).To(Equal(someResource{"Smith"}))
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+})
@@ -0,0 +1,95 @@
+package restresourcehandler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Response is a decoded resource (or a collection of resources) along with the metadata of the response.
+type Response[T any] struct {
+	// Data is the decoded resource.
+	Data T
+	ResponseMetadata
+}
+
+// ResponseMetadata describes a server response apart from the decoded resource
+// (see e.g. RestResourceHandler.FetchWithMetadata).
+type ResponseMetadata struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Header contains the response headers.
+	Header http.Header
+	// RequestID is the value of the "X-Request-Id" response header (empty if it has not been sent).
+	RequestID string
+	// RateLimit describes the rate limit state sent in the response headers (nil if it has not been sent).
+	RateLimit *RateLimit
+	// Links are the HATEOAS links sent along the response (see Config.LinksPropertyName).
+	Links Links
+	// Meta is the raw meta property sent along the response (see Config.MetaPropertyName, nil if there was none).
+	Meta RawValue
+	// Latency is the time from sending the first attempt of the request until receiving the final response
+	// (i.e. including all retries, see RetryPolicy).
+	Latency time.Duration
+	// Attempts is the number of attempts made to send the request.
+	Attempts int
+}
+
+// RateLimit describes the rate limit state sent by the server in the
+// "X-RateLimit-Limit", "X-RateLimit-Remaining" and "X-RateLimit-Reset" headers.
+type RateLimit struct {
+	// Limit is the number of requests allowed in the current rate limit window (-1 if it has not been sent).
+	Limit int
+	// Remaining is the number of requests left in the current rate limit window (-1 if it has not been sent).
+	Remaining int
+	// Reset is the time the current rate limit window ends (zero if it has not been sent).
+	// The header can hold either a Unix timestamp or the number of seconds until the reset.
+	Reset time.Time
+}
+
+// unixTimestampThreshold is the smallest "X-RateLimit-Reset" value treated as a Unix timestamp
+// (rather than a number of seconds).
+const unixTimestampThreshold = 1_000_000_000
+
+// newResponseMetadata extracts the metadata of the given response received at the given time.
+func newResponseMetadata(resp *http.Response, receivedAt time.Time) ResponseMetadata {
+	return ResponseMetadata{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+		RateLimit:  parseRateLimit(resp.Header, receivedAt),
+	}
+}
+
+// parseRateLimit parses the rate limit headers. Returns nil if none of them has been sent.
+func parseRateLimit(header http.Header, now time.Time) *RateLimit {
+	limit, hasLimit := parseIntHeader(header, "X-RateLimit-Limit")
+	remaining, hasRemaining := parseIntHeader(header, "X-RateLimit-Remaining")
+	reset, hasReset := parseIntHeader(header, "X-RateLimit-Reset")
+
+	if !hasLimit && !hasRemaining && !hasReset {
+		return nil
+	}
+
+	rateLimit := RateLimit{Limit: limit, Remaining: remaining}
+
+	switch {
+	case !hasReset:
+	case reset >= unixTimestampThreshold:
+		rateLimit.Reset = time.Unix(int64(reset), 0)
+	default:
+		rateLimit.Reset = now.Add(time.Duration(reset) * time.Second)
+	}
+
+	return &rateLimit
+}
+
+// parseIntHeader parses an integer header. Returns -1 and false if the header is missing or invalid.
+func parseIntHeader(header http.Header, key string) (int, bool) {
+	value, err := strconv.Atoi(header.Get(key))
+	if err != nil {
+		return -1, false
+	}
+
+	return value, true
+}
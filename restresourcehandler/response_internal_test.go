@@ -0,0 +1,34 @@
+package restresourcehandler
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("parseRateLimit", func() {
+	now := time.Date(2022, 1, 25, 12, 0, 0, 0, time.UTC)
+
+	DescribeTable("parses rate limit headers",
+		func(header http.Header, expected *RateLimit) {
+			Expect(parseRateLimit(header, now)).To(Equal(expected))
+		},
+		Entry("without headers", http.Header{}, nil),
+		Entry("with invalid headers", http.Header{"X-Ratelimit-Limit": []string{"many"}}, nil),
+		Entry("with all headers and reset timestamp",
+			http.Header{
+				"X-Ratelimit-Limit":     []string{"100"},
+				"X-Ratelimit-Remaining": []string{"0"},
+				"X-Ratelimit-Reset":     []string{"1643112060"},
+			},
+			&RateLimit{Limit: 100, Remaining: 0, Reset: time.Unix(1643112060, 0)}),
+		Entry("with reset in seconds",
+			http.Header{"X-Ratelimit-Reset": []string{"30"}},
+			&RateLimit{Limit: -1, Remaining: -1, Reset: now.Add(30 * time.Second)}),
+		Entry("with remaining only",
+			http.Header{"X-Ratelimit-Remaining": []string{"5"}},
+			&RateLimit{Limit: -1, Remaining: 5}),
+	)
+})
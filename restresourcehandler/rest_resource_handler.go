@@ -2,32 +2,40 @@ package restresourcehandler
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
 )
 
 // RestResourceHandler is used to query or update a REST API resource.
 // Avoid cerating instances of RestResourceHandler directly.
-// Rather use the NewRestResourceHandler function.
+// Rather use the New or MustNew function.
 type RestResourceHandler struct {
 	client      *http.Client
 	config      Config
 	resourceURL url.URL
 }
 
-// NewRestResourceHandler creates a new RestResourceHandler instance
-// for a given Config, HTTP client isntance and resource URL
-// (e.g. "http://example.com/api/qualifier/resource").
-func NewRestResourceHandler(httpClient *http.Client, resourceURL string, config Config) *RestResourceHandler {
-	validateRestResourceHandlerConfig(config)
+// New creates a new RestResourceHandler instance
+// for a given HTTP client isntance, resource URL
+// (e.g. "http://example.com/api/qualifier/resource") and Config.
+// Returns an error wrapping ErrInvalidConfig (see ConfigError) if the configuration is invalid.
+func New(httpClient *http.Client, resourceURL string, config Config) (*RestResourceHandler, error) {
+	if httpClient == nil {
+		return nil, &ConfigError{"HTTPClient", "http client must be set"}
+	}
+
+	if err := validateRestResourceHandlerConfig(config); err != nil {
+		return nil, err
+	}
 
 	url, err := url.Parse(resourceURL)
 	if err != nil {
-		panic(err)
+		return nil, &ConfigError{"ResourceURL", fmt.Sprintf("resource url is invalid: %v", err)}
 	}
 
 	if !url.IsAbs() {
-		panic("resource url must be absolute")
+		return nil, &ConfigError{"ResourceURL", "resource url must be absolute"}
 	}
 
 	handler := RestResourceHandler{
@@ -36,7 +44,27 @@ func NewRestResourceHandler(httpClient *http.Client, resourceURL string, config
 		resourceURL: *url,
 	}
 
-	return &handler
+	return &handler, nil
+}
+
+// MustNew is like New, but panics if the handler cannot be created.
+func MustNew(httpClient *http.Client, resourceURL string, config Config) *RestResourceHandler {
+	handler, err := New(httpClient, resourceURL, config)
+	if err != nil {
+		panic(err)
+	}
+
+	return handler
+}
+
+// NewRestResourceHandler creates a new RestResourceHandler instance
+// for a given Config, HTTP client isntance and resource URL
+// (e.g. "http://example.com/api/qualifier/resource").
+// Panics if the handler cannot be created.
+//
+// Deprecated: use New or MustNew instead.
+func NewRestResourceHandler(httpClient *http.Client, resourceURL string, config Config) *RestResourceHandler {
+	return MustNew(httpClient, resourceURL, config)
 }
 
 // Fetch fetches a resource for a given id, query parameters.
@@ -47,15 +75,31 @@ func (c *RestResourceHandler) Fetch(
 	resourceID string,
 	queryParams map[string]string,
 	resp interface{}) error {
-	return c.request(
+	_, err := c.FetchWithMetadata(ctx, resourceID, queryParams, resp)
+
+	return err
+}
+
+// FetchWithMetadata is like Fetch, but also returns the metadata of the response
+// (also if the server returned an error response).
+func (c *RestResourceHandler) FetchWithMetadata(
+	ctx context.Context,
+	resourceID string,
+	queryParams map[string]string,
+	resp interface{}) (ResponseMetadata, error) {
+	var metadata ResponseMetadata
+	err := c.request(
 		ctx,
 		requestParams{
 			HTTPMethod:     http.MethodGet,
 			ResourceID:     resourceID,
 			QueryParams:    queryParams,
 			Response:       resp,
+			Metadata:       &metadata,
 			ExpectedStatus: http.StatusOK,
 		})
+
+	return metadata, err
 }
 
 // Delete deletes a resource with a given id.
@@ -65,15 +109,30 @@ func (c *RestResourceHandler) Delete(
 	ctx context.Context,
 	resourceID string,
 	queryParams map[string]string) error {
-	return c.request(
+	_, err := c.DeleteWithMetadata(ctx, resourceID, queryParams)
+
+	return err
+}
+
+// DeleteWithMetadata is like Delete, but also returns the metadata of the response
+// (also if the server returned an error response).
+func (c *RestResourceHandler) DeleteWithMetadata(
+	ctx context.Context,
+	resourceID string,
+	queryParams map[string]string) (ResponseMetadata, error) {
+	var metadata ResponseMetadata
+	err := c.request(
 		ctx,
 		requestParams{
 			HTTPMethod:       http.MethodDelete,
 			ResourceID:       resourceID,
 			QueryParams:      queryParams,
 			DoDiscardContent: true,
+			Metadata:         &metadata,
 			ExpectedStatus:   http.StatusNoContent,
 		})
+
+	return metadata, err
 }
 
 // Create creates a resource given in the resourceToCreate parameter
@@ -83,13 +142,98 @@ func (c *RestResourceHandler) Create(
 	ctx context.Context,
 	resourceToCreate interface{},
 	resp interface{}) error {
-	return c.request(
+	_, err := c.CreateWithMetadata(ctx, resourceToCreate, resp)
+
+	return err
+}
+
+// CreateWithMetadata is like Create, but also returns the metadata of the response
+// (also if the server returned an error response).
+func (c *RestResourceHandler) CreateWithMetadata(
+	ctx context.Context,
+	resourceToCreate interface{},
+	resp interface{}) (ResponseMetadata, error) {
+	var metadata ResponseMetadata
+	err := c.request(
 		ctx,
 		requestParams{
 			HTTPMethod:          http.MethodPost,
 			DoDiscardResourceID: true,
 			Resource:            resourceToCreate,
 			Response:            resp,
+			Metadata:            &metadata,
 			ExpectedStatus:      http.StatusCreated,
 		})
+
+	return metadata, err
+}
+
+// Patch updates a resource with a given id using the patch given in the resourcePatch parameter
+// (i.e. an object containing only the changed properties) and stores the response in the resp parameter.
+// Context can be used to control asynchronous requests.
+func (c *RestResourceHandler) Patch(
+	ctx context.Context,
+	resourceID string,
+	resourcePatch interface{},
+	resp interface{}) error {
+	_, err := c.PatchWithMetadata(ctx, resourceID, resourcePatch, resp)
+
+	return err
+}
+
+// PatchWithMetadata is like Patch, but also returns the metadata of the response
+// (also if the server returned an error response).
+func (c *RestResourceHandler) PatchWithMetadata(
+	ctx context.Context,
+	resourceID string,
+	resourcePatch interface{},
+	resp interface{}) (ResponseMetadata, error) {
+	var metadata ResponseMetadata
+	err := c.request(
+		ctx,
+		requestParams{
+			HTTPMethod:     http.MethodPatch,
+			ResourceID:     resourceID,
+			Resource:       resourcePatch,
+			Response:       resp,
+			Metadata:       &metadata,
+			ExpectedStatus: http.StatusOK,
+		})
+
+	return metadata, err
+}
+
+// List fetches a collection of resources for given query parameters
+// (e.g. paging or filtering parameters).
+// resp is an output parameter that the fetched objects will be stored in.
+// Returns the links sent along the collection (empty if Config.LinksPropertyName is not set).
+// Context can be used to control asynchronous requests.
+func (c *RestResourceHandler) List(
+	ctx context.Context,
+	queryParams map[string]string,
+	resp interface{}) (Links, error) {
+	metadata, err := c.ListWithMetadata(ctx, queryParams, resp)
+
+	return metadata.Links, err
+}
+
+// ListWithMetadata is like List, but returns all the metadata of the response (including the links)
+// (also if the server returned an error response).
+func (c *RestResourceHandler) ListWithMetadata(
+	ctx context.Context,
+	queryParams map[string]string,
+	resp interface{}) (ResponseMetadata, error) {
+	var metadata ResponseMetadata
+	err := c.request(
+		ctx,
+		requestParams{
+			HTTPMethod:          http.MethodGet,
+			DoDiscardResourceID: true,
+			QueryParams:         queryParams,
+			Response:            resp,
+			Metadata:            &metadata,
+			ExpectedStatus:      http.StatusOK,
+		})
+
+	return metadata, err
 }
@@ -1,6 +1,10 @@
 package restresourcehandler
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+	"time"
+)
 
 // RemoteErrorExtractor is a function prototype for functions
 // which extract additional data from an error server response.
@@ -11,32 +15,204 @@ type Config struct {
 	// RemoteErrorExtractor is a function that should extract
 	// additional data from an error response.
 	RemoteErrorExtractor RemoteErrorExtractor
-	// ResourceEncoding denotes the encoding to be used when
-	// encoding (request) or decoding (response) server data.
-	// Currently only JSON encodings are supported.
+	// ResourceEncoding denotes the content type of the JSON encoding to be used when
+	// encoding (request) or decoding (response) server data (e.g. "application/json; charset=utf-8").
+	// It is a shorthand for setting Codec to a JSONCodec and must not be set along with Codec.
 	ResourceEncoding string
+	// Codec is the codec used to encode requests and to decode responses (see Codec).
+	// Either Codec or ResourceEncoding must be set.
+	Codec Codec
+	// AcceptedCodecs are optional additional codecs responses can be decoded with.
+	// The codec used to decode a response is chosen by the "Content-Type" of the response
+	// (Codec is used if the content type matches none of the codecs).
+	AcceptedCodecs []Codec
 	// IsDataWrapped denotes if the DTOs in server responses
-	// should be deserialized from the root of the JSON response (false)
-	// or are rather nested in the response JSON
+	// should be deserialized from the root of the response (false)
+	// or are rather nested in an envelope (see Codec.Wrap)
 	// (true, e.g. a HATEOAS response wraps the DTO in a root "data" property).
 	IsDataWrapped bool
-	// DataPropertyName is the property name in the response JSON
+	// DataPropertyName is the property name in the response envelope
 	// in which the response DTO should be looked for
 	// (in case IsDataWrapped is true).
 	DataPropertyName string
+	// LinksPropertyName is the property name in the response envelope
+	// in which the HATEOAS links (e.g. links to the next page of a collection)
+	// should be looked for (in case IsDataWrapped is true).
+	// Links are not read if LinksPropertyName is empty.
+	LinksPropertyName string
+	// MetaPropertyName is the property name in the response envelope
+	// in which additional response information (e.g. the total number of resources) should be looked for
+	// (in case IsDataWrapped is true). It is stored as a RawValue (see ResponseMetadata.Meta).
+	// Meta is not read if MetaPropertyName is empty.
+	MetaPropertyName string
+	// MaxResponseSize is the maximal size of a response body in bytes (0 - no limit).
+	// Reading a larger response fails with ResponseSizeError.
+	// The limit does not apply to error responses, which are read by the RemoteErrorExtractor
+	// (the default one reads at most MaxRemoteErrorBodyLength bytes).
+	MaxResponseSize int64
+	// RetryPolicy is an optional policy of retrying failed requests
+	// (nil - requests are not retried).
+	RetryPolicy *RetryPolicy
+	// IdempotencyKey is an optional generator of the idempotency keys of create (POST) operations
+	// (nil - no key is sent). The key is generated once per operation and sent in the "Idempotency-Key" header
+	// of every attempt, which allows the server to recognize retries (see RetryPolicy, only POST requests
+	// with an idempotency key are retried). See also RandomIdempotencyKey.
+	IdempotencyKey IdempotencyKeyFunc
+	// Header contains headers sent with every request (e.g. "User-Agent").
+	Header http.Header
+	// Timeout limits the duration of a single operation, including all its retries
+	// (0 - no limit apart from the deadline of the passed in context).
+	Timeout time.Duration
+	// CircuitBreaker is an optional circuit breaker of the resource endpoint (nil - requests are always sent).
+	// Requests rejected by an open circuit fail with CircuitOpenError and are not retried.
+	CircuitBreaker *CircuitBreaker
+	// RateLimiter is an optional limiter of the rate of sent requests (nil - the rate is not limited).
+	// Every request attempt waits for the limiter and every response is passed to RateLimiter.Observe.
+	// A limiter can be shared by multiple handlers. It has to be constructed with NewRateLimiter.
+	RateLimiter *RateLimiter
+	// AuthProvider is an optional provider of request credentials (nil - requests are not authenticated).
+	// Requests rejected with HTTP status 401 are authenticated and sent once more
+	// after invalidating the credentials.
+	AuthProvider AuthProvider
+	// Interceptors are optional interceptors of the performed operations (see Interceptor).
+	// The first interceptor is the outermost one, i.e. it is called first and sees the final result.
+	// Interceptors are called once per operation (not per request attempt, see RetryPolicy)
+	// and within the operation Timeout.
+	Interceptors []Interceptor
+	// Metrics is an optional receiver of the measurements of the performed operations
+	// (nil - nothing is measured, see Metrics and InMemoryMetrics).
+	Metrics Metrics
+	// Tracer is an optional tracer of the performed operations (nil - nothing is traced, see Tracer).
+	Tracer Tracer
+	// ResourceName is the name of the handled resource used in metrics and traces
+	// (see MetricLabels.Resource and AttributeResource).
+	// The path of the resource URL is used if ResourceName is empty.
+	ResourceName string
+	// Logger is an optional logger of the sent requests and received responses
+	// (nil - nothing is logged).
+	Logger Logger
+	// LogLevel is the minimal level of the logged messages (default LogLevelDebug - everything is logged).
+	LogLevel LogLevel
+	// LogBodies enables debug logging of the request and response bodies.
+	// Response bodies are then read whole before being decoded (they are not streamed).
+	LogBodies bool
+	// Redactor is an optional redactor of the logged and traced URLs and the logged bodies
+	// (nil - nothing is redacted, see FieldRedactor).
+	Redactor Redactor
 }
 
 // validateRestResourceHandlerConfig does a sanity check of a Config instance.
-func validateRestResourceHandlerConfig(config Config) {
+func validateRestResourceHandlerConfig(config Config) error {
 	if config.IsDataWrapped && config.DataPropertyName == "" {
-		panic("IsDataWrapped is set, but DataPropertyName has not been given.")
+		return &ConfigError{"DataPropertyName", "IsDataWrapped is set, but DataPropertyName has not been given."}
 	}
 
 	if !config.IsDataWrapped && config.DataPropertyName != "" {
-		panic("IsDataWrapped is not set, but DataPropertyName has been given.")
+		return &ConfigError{"DataPropertyName", "IsDataWrapped is not set, but DataPropertyName has been given."}
 	}
 
-	if config.ResourceEncoding == "" {
-		panic("ResourceEncoding must be set.")
+	if !config.IsDataWrapped && config.LinksPropertyName != "" {
+		return &ConfigError{"LinksPropertyName", "IsDataWrapped is not set, but LinksPropertyName has been given."}
 	}
+
+	if !config.IsDataWrapped && config.MetaPropertyName != "" {
+		return &ConfigError{"MetaPropertyName", "IsDataWrapped is not set, but MetaPropertyName has been given."}
+	}
+
+	if config.ResourceEncoding == "" && config.Codec == nil {
+		return &ConfigError{"ResourceEncoding", "ResourceEncoding must be set."}
+	}
+
+	if config.ResourceEncoding != "" && config.Codec != nil {
+		return &ConfigError{"Codec", "Codec is set, but ResourceEncoding has been given."}
+	}
+
+	for _, codec := range config.AcceptedCodecs {
+		if codec == nil {
+			return &ConfigError{"AcceptedCodecs", "AcceptedCodecs must not contain nil codecs."}
+		}
+	}
+
+	if config.MaxResponseSize < 0 {
+		return &ConfigError{"MaxResponseSize", "MaxResponseSize must not be negative."}
+	}
+
+	if config.Timeout < 0 {
+		return &ConfigError{"Timeout", "Timeout must not be negative."}
+	}
+
+	for _, interceptor := range config.Interceptors {
+		if interceptor == nil {
+			return &ConfigError{"Interceptors", "Interceptors must not contain nil interceptors."}
+		}
+	}
+
+	if config.RateLimiter != nil && !config.RateLimiter.isValid() {
+		return &ConfigError{"RateLimiter", "RateLimiter must be constructed with NewRateLimiter."}
+	}
+
+	if config.RetryPolicy != nil {
+		return validateRetryPolicy(*config.RetryPolicy)
+	}
+
+	return nil
+}
+
+func (config Config) logger() Logger {
+	if config.Logger == nil {
+		return noopLogger{}
+	}
+
+	return leveledLogger{config.Logger, config.LogLevel}
+}
+
+func (config Config) redactor() Redactor {
+	if config.Redactor == nil {
+		return noopRedactor{}
+	}
+
+	return config.Redactor
+}
+
+// isBodyLogged reports whether the request and response bodies are logged (see Config.LogBodies).
+func (config Config) isBodyLogged() bool {
+	return config.Logger != nil && config.LogBodies && config.LogLevel <= LogLevelDebug
+}
+
+func (config Config) tracer() Tracer {
+	if config.Tracer == nil {
+		return noopTracer{}
+	}
+
+	return config.Tracer
+}
+
+// codec returns the codec used to encode requests.
+func (config Config) codec() Codec {
+	if config.Codec == nil {
+		return JSONCodec{MediaType: config.ResourceEncoding}
+	}
+
+	return config.Codec
+}
+
+// accept returns the value of the "Accept" header, listing the content types of all codecs.
+func (config Config) accept() string {
+	contentTypes := []string{config.codec().ContentType()}
+	for _, codec := range config.AcceptedCodecs {
+		contentTypes = append(contentTypes, codec.ContentType())
+	}
+
+	return strings.Join(contentTypes, ", ")
+}
+
+// responseCodec returns the codec for a response with the given content type.
+func (config Config) responseCodec(contentType string) Codec {
+	for _, codec := range config.AcceptedCodecs {
+		if isSameMediaType(contentType, codec.ContentType()) {
+			return codec
+		}
+	}
+
+	return config.codec()
 }
@@ -1,6 +1,8 @@
 package restresourcehandler
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -12,14 +14,18 @@ func someValidRestResourceHandlerConfig() Config {
 }
 
 var _ = Describe("RestResourceHandlerConfig", func() {
-	Context("panics during validation", func() {
+	It("passes validation", func() {
+		Expect(validateRestResourceHandlerConfig(someValidRestResourceHandlerConfig())).To(Succeed())
+	})
+
+	Context("fails validation", func() {
 		It("when data is wrapped but no property name has been set", func() {
 			config := someValidRestResourceHandlerConfig()
 			config.IsDataWrapped = true
 			config.DataPropertyName = ""
 
-			Expect(func() { validateRestResourceHandlerConfig(config) }).
-				To(PanicWith("IsDataWrapped is set, but DataPropertyName has not been given."))
+			Expect(validateRestResourceHandlerConfig(config)).
+				To(MatchError(&ConfigError{"DataPropertyName", "IsDataWrapped is set, but DataPropertyName has not been given."}))
 		})
 
 		It("when data is not wrapped but property name has been set", func() {
@@ -27,16 +33,90 @@ var _ = Describe("RestResourceHandlerConfig", func() {
 			config.IsDataWrapped = false
 			config.DataPropertyName = "someproperty"
 
-			Expect(func() { validateRestResourceHandlerConfig(config) }).
-				To(PanicWith("IsDataWrapped is not set, but DataPropertyName has been given."))
+			Expect(validateRestResourceHandlerConfig(config)).
+				To(MatchError(&ConfigError{"DataPropertyName", "IsDataWrapped is not set, but DataPropertyName has been given."}))
+		})
+
+		It("when data is not wrapped but links property name has been set", func() {
+			config := someValidRestResourceHandlerConfig()
+			config.IsDataWrapped = false
+			config.LinksPropertyName = "links"
+
+			Expect(validateRestResourceHandlerConfig(config)).
+				To(MatchError(&ConfigError{"LinksPropertyName", "IsDataWrapped is not set, but LinksPropertyName has been given."}))
+		})
+
+		It("when data is not wrapped but meta property name has been set", func() {
+			config := someValidRestResourceHandlerConfig()
+			config.IsDataWrapped = false
+			config.MetaPropertyName = "meta"
+
+			Expect(validateRestResourceHandlerConfig(config)).
+				To(MatchError(&ConfigError{"MetaPropertyName", "IsDataWrapped is not set, but MetaPropertyName has been given."}))
 		})
 
 		It("when resource enoding has not been set", func() {
 			config := someValidRestResourceHandlerConfig()
 			config.ResourceEncoding = ""
 
-			Expect(func() { validateRestResourceHandlerConfig(config) }).
-				To(PanicWith("ResourceEncoding must be set."))
+			Expect(validateRestResourceHandlerConfig(config)).
+				To(MatchError(&ConfigError{"ResourceEncoding", "ResourceEncoding must be set."}))
+		})
+
+		It("when both codec and resource encoding have been set", func() {
+			config := someValidRestResourceHandlerConfig()
+			config.Codec = XMLCodec{}
+
+			Expect(validateRestResourceHandlerConfig(config)).
+				To(MatchError(&ConfigError{"Codec", "Codec is set, but ResourceEncoding has been given."}))
+		})
+
+		It("when an accepted codec is nil", func() {
+			config := someValidRestResourceHandlerConfig()
+			config.AcceptedCodecs = []Codec{nil}
+
+			Expect(validateRestResourceHandlerConfig(config)).
+				To(MatchError(&ConfigError{"AcceptedCodecs", "AcceptedCodecs must not contain nil codecs."}))
+		})
+
+		It("when maximal response size is negative", func() {
+			config := someValidRestResourceHandlerConfig()
+			config.MaxResponseSize = -1
+
+			Expect(validateRestResourceHandlerConfig(config)).
+				To(MatchError(&ConfigError{"MaxResponseSize", "MaxResponseSize must not be negative."}))
+		})
+
+		It("when timeout is negative", func() {
+			config := someValidRestResourceHandlerConfig()
+			config.Timeout = -time.Second
+
+			Expect(validateRestResourceHandlerConfig(config)).
+				To(MatchError(&ConfigError{"Timeout", "Timeout must not be negative."}))
+		})
+
+		It("when an interceptor is nil", func() {
+			config := someValidRestResourceHandlerConfig()
+			config.Interceptors = []Interceptor{nil}
+
+			Expect(validateRestResourceHandlerConfig(config)).
+				To(MatchError(&ConfigError{"Interceptors", "Interceptors must not contain nil interceptors."}))
+		})
+
+		It("when rate limiter has not been constructed with NewRateLimiter", func() {
+			config := someValidRestResourceHandlerConfig()
+			config.RateLimiter = &RateLimiter{}
+
+			Expect(validateRestResourceHandlerConfig(config)).
+				To(MatchError(&ConfigError{"RateLimiter", "RateLimiter must be constructed with NewRateLimiter."}))
+		})
+
+		It("when retry policy is invalid", func() {
+			config := someValidRestResourceHandlerConfig()
+			config.RetryPolicy = &RetryPolicy{}
+
+			Expect(validateRestResourceHandlerConfig(config)).
+				To(MatchError(&ConfigError{"RetryPolicy.MaxAttempts", "RetryPolicy.MaxAttempts must be positive."}))
 		})
 	})
 })
@@ -3,14 +3,19 @@ package restresourcehandler_test
 import (
 	"context"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
+	"github.com/onsi/gomega/types"
 )
 
 type person struct {
@@ -21,6 +26,11 @@ type wrapper struct {
 	Data person `json:"data"`
 }
 
+type collectionWrapper struct {
+	Data  []person                  `json:"data"`
+	Links restresourcehandler.Links `json:"links"`
+}
+
 type apiError struct {
 	ErrorMessage string `json:"error_message"`
 }
@@ -42,6 +52,17 @@ func getExampleValidAPICalls() map[string]apiCall {
 
 			return client.Create(context.Background(), person{"Smith"}, &response) //nolint:wrapcheck,lll // we need this error unwrapped
 		},
+		"patch": func(client *restresourcehandler.RestResourceHandler) error {
+			var response person
+
+			return client.Patch(context.Background(), "1", person{"Smith"}, &response) //nolint:wrapcheck,lll // we need this error unwrapped
+		},
+		"list": func(client *restresourcehandler.RestResourceHandler) error {
+			var response []person
+			_, err := client.List(context.Background(), map[string]string{"page[size]": "2"}, &response)
+
+			return err //nolint:wrapcheck // we need this error unwrapped
+		},
 	}
 }
 
@@ -60,6 +81,67 @@ func remoteErrorWithServerMessage(httpStatusCode int, serverMessage string) erro
 		serverMessage)
 }
 
+type logEntry struct {
+	Level   string
+	Message string
+	KeyVals []interface{}
+}
+
+// recordingLogger is a restresourcehandler.Logger which records all logged entries.
+type recordingLogger struct {
+	entries []logEntry
+}
+
+func (l *recordingLogger) Debug(msg string, keyvals ...interface{}) { l.record("debug", msg, keyvals) }
+func (l *recordingLogger) Info(msg string, keyvals ...interface{})  { l.record("info", msg, keyvals) }
+func (l *recordingLogger) Warn(msg string, keyvals ...interface{})  { l.record("warn", msg, keyvals) }
+func (l *recordingLogger) Error(msg string, keyvals ...interface{}) { l.record("error", msg, keyvals) }
+
+func (l *recordingLogger) record(level string, msg string, keyvals []interface{}) {
+	l.entries = append(l.entries, logEntry{level, msg, keyvals})
+}
+
+func (l *recordingLogger) messages() []string {
+	messages := make([]string, len(l.entries))
+	for i, entry := range l.entries {
+		messages[i] = entry.Level + ": " + entry.Message
+	}
+
+	return messages
+}
+
+// fakeAuthProvider is a restresourcehandler.AuthProvider issuing a new token after each invalidation.
+type fakeAuthProvider struct {
+	tokenNumber   int
+	invalidations int
+}
+
+func (p *fakeAuthProvider) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer token-%d", p.tokenNumber))
+
+	return nil
+}
+
+func (p *fakeAuthProvider) Invalidate(req *http.Request) {
+	p.invalidations++
+	p.tokenNumber++
+}
+
+func beRemoteError(statusCode int) types.GomegaMatcher {
+	return SatisfyAll(
+		MatchError(restresourcehandler.ErrRemoteError),
+		WithTransform(
+			func(err error) restresourcehandler.RemoteError {
+				var remoteError *restresourcehandler.RemoteError
+				if !errors.As(err, &remoteError) {
+					return restresourcehandler.RemoteError{}
+				}
+
+				return *remoteError
+			},
+			HaveField("StatusCode", statusCode)))
+}
+
 var _ = Describe("RestResourceHandler", func() {
 	var server *ghttp.Server
 	var httpClient *http.Client
@@ -78,17 +160,75 @@ var _ = Describe("RestResourceHandler", func() {
 		server.Close()
 	})
 
+	Context("on construction", func() {
+		It("returns handler for valid parameters", func() {
+			client, err := restresourcehandler.New(
+				httpClient,
+				url,
+				restresourcehandler.Config{ResourceEncoding: resourceEncoding})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client).NotTo(BeNil())
+		})
+
+		It("returns error for invalid config", func() {
+			client, err := restresourcehandler.New(httpClient, url, restresourcehandler.Config{})
+
+			Expect(err).To(MatchError(restresourcehandler.ErrInvalidConfig))
+			Expect(err).To(MatchError(&restresourcehandler.ConfigError{
+				Property: "ResourceEncoding",
+				Message:  "ResourceEncoding must be set.",
+			}))
+			Expect(client).To(BeNil())
+		})
+
+		It("returns error for relative resource url", func() {
+			client, err := restresourcehandler.New(
+				httpClient,
+				resourcePath,
+				restresourcehandler.Config{ResourceEncoding: resourceEncoding})
+
+			Expect(err).To(MatchError(&restresourcehandler.ConfigError{
+				Property: "ResourceURL",
+				Message:  "resource url must be absolute",
+			}))
+			Expect(client).To(BeNil())
+		})
+
+		It("returns error for invalid resource url", func() {
+			_, err := restresourcehandler.New(
+				httpClient,
+				"http://example.com/%zz",
+				restresourcehandler.Config{ResourceEncoding: resourceEncoding})
+
+			Expect(err).To(MatchError(restresourcehandler.ErrInvalidConfig))
+		})
+
+		It("returns error for missing http client", func() {
+			_, err := restresourcehandler.New(nil, url, restresourcehandler.Config{ResourceEncoding: resourceEncoding})
+
+			Expect(err).To(MatchError(restresourcehandler.ErrInvalidConfig))
+		})
+
+		It("panics in MustNew for invalid config", func() {
+			Expect(func() {
+				restresourcehandler.MustNew(httpClient, url, restresourcehandler.Config{})
+			}).To(PanicWith(MatchError(restresourcehandler.ErrInvalidConfig)))
+		})
+	})
+
 	Context("on happy-path", func() {
 		var client *restresourcehandler.RestResourceHandler
 
 		BeforeEach(func() {
-			client = restresourcehandler.NewRestResourceHandler(
+			client = restresourcehandler.MustNew(
 				httpClient,
 				url,
 				restresourcehandler.Config{
-					IsDataWrapped:    true,
-					DataPropertyName: "data",
-					ResourceEncoding: resourceEncoding,
+					IsDataWrapped:     true,
+					DataPropertyName:  "data",
+					LinksPropertyName: "links",
+					ResourceEncoding:  resourceEncoding,
 				})
 		})
 
@@ -137,13 +277,72 @@ var _ = Describe("RestResourceHandler", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actualResponse).To(Equal(expectedResponse))
 		})
+
+		It("patches resource", func() {
+			payload := person{"Smith"}
+			expectedResponse := person{"Gennings"}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PATCH", resourcePath+"/1"),
+					ghttp.VerifyContentType(resourceEncoding),
+					ghttp.VerifyHeaderKV("Accept", resourceEncoding),
+					ghttp.VerifyJSONRepresenting(wrapper{payload}),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{expectedResponse})))
+
+			var actualResponse person
+			err := client.Patch(context.Background(), "1", payload, &actualResponse)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualResponse).To(Equal(expectedResponse))
+		})
+
+		It("lists resources", func() {
+			expectedPeople := []person{{"Smith"}, {"Gennings"}}
+			expectedLinks := restresourcehandler.Links{
+				Self: resourcePath + "?page[number]=0&page[size]=2",
+				Next: resourcePath + "?page[number]=1&page[size]=2",
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", resourcePath, "page[number]=0&page[size]=2"),
+					ghttp.VerifyHeaderKV("Accept", resourceEncoding),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, collectionWrapper{expectedPeople, expectedLinks})))
+
+			var response []person
+			links, err := client.List(
+				context.Background(),
+				map[string]string{"page[number]": "0", "page[size]": "2"},
+				&response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(expectedPeople))
+			Expect(links).To(Equal(expectedLinks))
+		})
+
+		It("lists resources when server sends no links", func() {
+			expectedPeople := []person{{"Smith"}}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", resourcePath),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, map[string][]person{"data": expectedPeople})))
+
+			var response []person
+			links, err := client.List(context.Background(), nil, &response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(expectedPeople))
+			Expect(links).To(BeZero())
+		})
 	})
 
 	Context("with default remote error extractor", func() {
 		var client *restresourcehandler.RestResourceHandler
 
 		BeforeEach(func() {
-			client = restresourcehandler.NewRestResourceHandler(
+			client = restresourcehandler.MustNew(
 				httpClient,
 				url,
 				restresourcehandler.Config{
@@ -151,16 +350,33 @@ var _ = Describe("RestResourceHandler", func() {
 				})
 			server.AppendHandlers(
 				ghttp.CombineHandlers(
-					ghttp.RespondWith(http.StatusInternalServerError, nil)))
+					ghttp.RespondWith(
+						http.StatusInternalServerError,
+						"some error details",
+						http.Header{"X-Request-Id": []string{"some-request-id"}})))
 		})
 
 		forEachExampleValidAPICall(func(reqName string, req apiCall) {
 			It(fmt.Sprintf(`provides default error during "%s" call`, reqName), func() {
 				err := req(client)
 
-				Expect(err).To(MatchError(restresourcehandler.RemoteError(http.StatusInternalServerError)))
+				Expect(err).To(beRemoteError(http.StatusInternalServerError))
 			})
 		})
+
+		It("provides error response details", func() {
+			err := client.Delete(context.Background(), "1", map[string]string{"version": "1"})
+
+			var remoteError *restresourcehandler.RemoteError
+			Expect(errors.As(err, &remoteError)).To(BeTrue())
+			Expect(remoteError.Method).To(Equal(http.MethodDelete))
+			Expect(remoteError.URL).To(Equal(url + "/1?version=1"))
+			Expect(remoteError.Header.Get("X-Request-Id")).To(Equal("some-request-id"))
+			Expect(remoteError.Body).To(Equal("some error details"))
+			Expect(remoteError.ServerMessage).To(BeEmpty())
+			Expect(restresourcehandler.IsRetryable(err)).To(BeTrue())
+			Expect(restresourcehandler.IsNotFound(err)).To(BeFalse())
+		})
 	})
 
 	Context("with custom remote error extractor", func() {
@@ -169,7 +385,7 @@ var _ = Describe("RestResourceHandler", func() {
 			customError := fmt.Errorf("some custom error") //nolint:goerr113 // not a problem here
 
 			BeforeEach(func() {
-				client = restresourcehandler.NewRestResourceHandler(
+				client = restresourcehandler.MustNew(
 					httpClient,
 					url,
 					restresourcehandler.Config{
@@ -197,7 +413,7 @@ var _ = Describe("RestResourceHandler", func() {
 			var client *restresourcehandler.RestResourceHandler
 
 			BeforeEach(func() {
-				client = restresourcehandler.NewRestResourceHandler(
+				client = restresourcehandler.MustNew(
 					httpClient,
 					url,
 					restresourcehandler.Config{
@@ -233,4 +449,936 @@ var _ = Describe("RestResourceHandler", func() {
 			})
 		})
 	})
+
+	Context("with retry policy", func() {
+		var client *restresourcehandler.RestResourceHandler
+
+		BeforeEach(func() {
+			client = restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					RetryPolicy: &restresourcehandler.RetryPolicy{
+						MaxAttempts: 3,
+						BaseDelay:   time.Millisecond,
+						MaxDelay:    10 * time.Millisecond,
+					},
+				})
+		})
+
+		It("retries fetch on server errors", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+				ghttp.RespondWith(http.StatusTooManyRequests, nil, http.Header{"Retry-After": []string{"120"}}),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, person{"Smith"}))
+
+			var response person
+			err := client.Fetch(context.Background(), "1", nil, &response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(person{"Smith"}))
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("retries delete on transport errors", func() {
+			server.AppendHandlers(
+				func(w http.ResponseWriter, r *http.Request) {
+					conn, _, err := w.(http.Hijacker).Hijack()
+					Expect(err).NotTo(HaveOccurred())
+					conn.Close()
+				},
+				ghttp.RespondWith(http.StatusNoContent, nil))
+
+			err := client.Delete(context.Background(), "1", nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("gives up after max attempts", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+				ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+				ghttp.RespondWith(http.StatusInternalServerError, nil))
+
+			var response person
+			err := client.Fetch(context.Background(), "1", nil, &response)
+
+			Expect(err).To(beRemoteError(http.StatusInternalServerError))
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("does not retry on client errors", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, nil))
+
+			var response person
+			err := client.Fetch(context.Background(), "1", nil, &response)
+
+			Expect(err).To(beRemoteError(http.StatusNotFound))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("does not retry non-idempotent create", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, nil))
+
+			var response person
+			err := client.Create(context.Background(), person{"Smith"}, &response)
+
+			Expect(err).To(beRemoteError(http.StatusServiceUnavailable))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("stops waiting for retry when context is cancelled", func() {
+			client = restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					RetryPolicy: &restresourcehandler.RetryPolicy{
+						MaxAttempts: 3,
+						BaseDelay:   time.Hour,
+						MaxDelay:    time.Hour,
+					},
+				})
+			server.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, nil, http.Header{"Retry-After": []string{"60"}}))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			err := client.Delete(ctx, "1", nil)
+
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		Context("and idempotency keys", func() {
+			var generatedKeys int
+
+			BeforeEach(func() {
+				generatedKeys = 0
+				client = restresourcehandler.MustNew(
+					httpClient,
+					url,
+					restresourcehandler.Config{
+						ResourceEncoding: resourceEncoding,
+						RetryPolicy:      &restresourcehandler.RetryPolicy{MaxAttempts: 3},
+						IdempotencyKey: func(resource interface{}) (string, error) {
+							generatedKeys++
+
+							return fmt.Sprintf("key-%d-%s", generatedKeys, resource.(person).Name), nil
+						},
+					})
+			})
+
+			It("retries create sending the same idempotency key", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyHeaderKV("Idempotency-Key", "key-1-Smith"),
+						ghttp.RespondWith(http.StatusServiceUnavailable, nil)),
+					ghttp.CombineHandlers(
+						ghttp.VerifyHeaderKV("Idempotency-Key", "key-1-Smith"),
+						ghttp.RespondWithJSONEncoded(http.StatusCreated, person{"Smith"})))
+
+				var response person
+				err := client.Create(context.Background(), person{"Smith"}, &response)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response).To(Equal(person{"Smith"}))
+				Expect(generatedKeys).To(Equal(1))
+			})
+
+			It("sends a new idempotency key with every create", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyHeaderKV("Idempotency-Key", "key-1-Smith"),
+						ghttp.RespondWithJSONEncoded(http.StatusCreated, person{"Smith"})),
+					ghttp.CombineHandlers(
+						ghttp.VerifyHeaderKV("Idempotency-Key", "key-2-Smith"),
+						ghttp.RespondWithJSONEncoded(http.StatusCreated, person{"Smith"})))
+
+				var response person
+				Expect(client.Create(context.Background(), person{"Smith"}, &response)).To(Succeed())
+				Expect(client.Create(context.Background(), person{"Smith"}, &response)).To(Succeed())
+			})
+
+			It("does not send idempotency key with other requests", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyHeader(http.Header{"Idempotency-Key": nil}),
+						ghttp.RespondWith(http.StatusNoContent, nil)))
+
+				Expect(client.Delete(context.Background(), "1", nil)).To(Succeed())
+				Expect(generatedKeys).To(BeZero())
+			})
+
+			It("fails create when idempotency key cannot be generated", func() {
+				someError := errors.New("some error")
+				client = restresourcehandler.MustNew(
+					httpClient,
+					url,
+					restresourcehandler.Config{
+						ResourceEncoding: resourceEncoding,
+						IdempotencyKey: func(interface{}) (string, error) {
+							return "", someError
+						},
+					})
+
+				var response person
+				err := client.Create(context.Background(), person{"Smith"}, &response)
+
+				Expect(err).To(MatchError(someError))
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+			})
+
+			It("generates random idempotency keys", func() {
+				key, err := restresourcehandler.RandomIdempotencyKey(nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				otherKey, err := restresourcehandler.RandomIdempotencyKey(nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(key).To(HaveLen(36))
+				Expect(key).NotTo(Equal(otherKey))
+			})
+		})
+	})
+
+	Context("with additional configuration", func() {
+		It("sends configured headers", func() {
+			client := restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					Header: http.Header{
+						"User-Agent":   []string{"some-agent/1.0"},
+						"X-Some-Value": []string{"a", "b"},
+					},
+				})
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("User-Agent", "some-agent/1.0"),
+					ghttp.VerifyHeaderKV("X-Some-Value", "a", "b"),
+					ghttp.RespondWith(http.StatusNoContent, nil)))
+
+			err := client.Delete(context.Background(), "1", nil)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("fails when operation exceeds timeout", func() {
+			client := restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					Timeout:          20 * time.Millisecond,
+				})
+			server.AppendHandlers(
+				func(w http.ResponseWriter, r *http.Request) {
+					time.Sleep(200 * time.Millisecond)
+					w.WriteHeader(http.StatusNoContent)
+				})
+
+			err := client.Delete(context.Background(), "1", nil)
+
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+		})
+
+		It("limits request rate adapting to server rate limit", func() {
+			limiter, err := restresourcehandler.NewRateLimiter(1000, 10)
+			Expect(err).NotTo(HaveOccurred())
+
+			client := restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					RateLimiter:      limiter,
+				})
+			server.AppendHandlers(
+				ghttp.RespondWith(
+					http.StatusNoContent,
+					nil,
+					http.Header{
+						"X-Ratelimit-Remaining": []string{"0"},
+						"X-Ratelimit-Reset":     []string{"60"},
+					}))
+
+			Expect(client.Delete(context.Background(), "1", nil)).To(Succeed())
+			Expect(limiter.State().BlockedUntil).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			err = client.Delete(ctx, "1", nil)
+
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("fails fast when circuit breaker is open", func() {
+			var transitions []string
+			breaker, err := restresourcehandler.NewCircuitBreaker(restresourcehandler.CircuitBreakerConfig{
+				Name:           "people",
+				FailureRatio:   1,
+				MinRequests:    2,
+				Window:         time.Minute,
+				CoolDown:       time.Minute,
+				HalfOpenProbes: 1,
+				OnStateChange: func(name string, from, to restresourcehandler.CircuitState) {
+					transitions = append(transitions, fmt.Sprintf("%s: %s -> %s", name, from, to))
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			client := restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					CircuitBreaker:   breaker,
+					RetryPolicy:      &restresourcehandler.RetryPolicy{MaxAttempts: 3},
+				})
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+				ghttp.RespondWith(http.StatusServiceUnavailable, nil))
+
+			err = client.Delete(context.Background(), "1", nil)
+
+			Expect(err).To(MatchError(restresourcehandler.ErrCircuitOpen))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+			Expect(breaker.State()).To(Equal(restresourcehandler.CircuitOpen))
+			Expect(transitions).To(Equal([]string{"people: closed -> open"}))
+
+			err = client.Delete(context.Background(), "1", nil)
+
+			Expect(err).To(MatchError(restresourcehandler.ErrCircuitOpen))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("records metrics of operations", func() {
+			metrics := restresourcehandler.NewInMemoryMetrics()
+			client := restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					ResourceName:     "people",
+					Metrics:          metrics,
+				})
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, person{"Smith"}),
+				ghttp.RespondWith(http.StatusNotFound, nil))
+
+			var response person
+			Expect(client.Fetch(context.Background(), "1", nil, &response)).To(Succeed())
+			Expect(client.Delete(context.Background(), "1", nil)).NotTo(Succeed())
+
+			var labels []restresourcehandler.MetricLabels
+			for _, series := range metrics.Snapshot() {
+				Expect(series.Count).To(Equal(uint64(1)))
+				labels = append(labels, series.Labels)
+			}
+
+			Expect(labels).To(Equal([]restresourcehandler.MetricLabels{
+				{Resource: "people", Method: "DELETE", StatusClass: "4xx", ErrorKind: restresourcehandler.ErrorKindRemote},
+				{Resource: "people", Method: "GET", StatusClass: "2xx"},
+			}))
+		})
+
+		Context("with maximal response size", func() {
+			var client *restresourcehandler.RestResourceHandler
+
+			BeforeEach(func() {
+				client = restresourcehandler.MustNew(
+					httpClient,
+					url,
+					restresourcehandler.Config{
+						ResourceEncoding: resourceEncoding,
+						IsDataWrapped:    true,
+						DataPropertyName: "data",
+						MaxResponseSize:  32,
+					})
+			})
+
+			It("reads response within the limit", func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{"data":{"name":"Smith"}}`))
+
+				var response person
+				err := client.Fetch(context.Background(), "1", nil, &response)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response).To(Equal(person{"Smith"}))
+			})
+
+			It("fails for response with content length exceeding the limit", func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{"data":{"name":"Smith"},"meta":"something"}`))
+
+				var response person
+				err := client.Fetch(context.Background(), "1", nil, &response)
+
+				Expect(err).To(MatchError(&restresourcehandler.ResponseSizeError{Limit: 32}))
+				Expect(err).To(MatchError(restresourcehandler.ErrResponseTooLarge))
+			})
+
+			It("fails for streamed response exceeding the limit", func() {
+				server.AppendHandlers(func(w http.ResponseWriter, req *http.Request) {
+					_, _ = w.Write([]byte(`{"meta":"something",`))
+					w.(http.Flusher).Flush()
+					_, _ = w.Write([]byte(`"data":{"name":"Smith"}}`))
+				})
+
+				var response person
+				err := client.Fetch(context.Background(), "1", nil, &response)
+
+				Expect(err).To(MatchError(restresourcehandler.ErrResponseTooLarge))
+			})
+
+			It("reports remote error with body exceeding the limit", func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusServiceUnavailable, strings.Repeat("service unavailable ", 10)))
+
+				var response person
+				err := client.Fetch(context.Background(), "1", nil, &response)
+
+				Expect(err).To(beRemoteError(http.StatusServiceUnavailable))
+				Expect(err).NotTo(MatchError(restresourcehandler.ErrResponseTooLarge))
+			})
+		})
+
+		It("logs requests, responses and retries", func() {
+			logger := &recordingLogger{}
+			client := restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					Logger:           logger,
+					RetryPolicy:      &restresourcehandler.RetryPolicy{MaxAttempts: 2},
+				})
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+				ghttp.RespondWith(http.StatusNoContent, nil))
+
+			err := client.Delete(context.Background(), "1", nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.messages()).To(Equal([]string{
+				"debug: sending http request",
+				"debug: received http response",
+				"warn: retrying http request",
+				"debug: sending http request",
+				"debug: received http response",
+			}))
+			Expect(logger.entries[1].KeyVals).To(ContainElements("status", http.StatusServiceUnavailable))
+		})
+
+		It("logs redacted bodies", func() {
+			logger := &recordingLogger{}
+			client := restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					Logger:           logger,
+					LogBodies:        true,
+					Redactor:         restresourcehandler.FieldRedactor{Fields: []string{"name"}},
+				})
+			server.AppendHandlers(ghttp.RespondWith(http.StatusCreated, `{"name":"Johnson"}`))
+
+			var response person
+			err := client.Create(context.Background(), person{"Smith"}, &response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(person{"Johnson"}))
+			Expect(logger.messages()).To(Equal([]string{
+				"debug: sending http request",
+				"debug: received http response",
+				"debug: received http response body",
+			}))
+			Expect(logger.entries[0].KeyVals).To(ContainElements("body", `{"name":"*mith"}`))
+			Expect(logger.entries[2].KeyVals).To(ContainElements("body", `{"name":"***nson"}`))
+		})
+
+		It("logs redacted urls", func() {
+			logger := &recordingLogger{}
+			client := restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					Logger:           logger,
+					Redactor:         restresourcehandler.FieldRedactor{Fields: []string{"name"}},
+				})
+			server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `[]`))
+
+			var response []person
+			_, err := client.List(context.Background(), map[string]string{"filter[name]": "Smith"}, &response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.entries[0].KeyVals).To(ContainElements("url", url+"?filter%5Bname%5D=%2Amith"))
+		})
+
+		It("redacts urls of transport errors", func() {
+			const someIban = "GB33BUKB20201555555555"
+			unreachableServer := ghttp.NewServer()
+			unreachableURL := unreachableServer.URL()
+			unreachableServer.Close()
+			logger := &recordingLogger{}
+			tracer := restresourcehandler.NewRecordingTracer()
+			client := restresourcehandler.MustNew(
+				httpClient,
+				unreachableURL+resourcePath,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					Logger:           logger,
+					Tracer:           tracer,
+					Redactor:         restresourcehandler.FieldRedactor{Fields: []string{"iban"}},
+				})
+
+			var response []person
+			_, err := client.List(context.Background(), map[string]string{"filter[iban]": someIban}, &response)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).NotTo(ContainSubstring(someIban))
+			Expect(logger.messages()).To(ContainElement("debug: http request failed"))
+			for _, entry := range logger.entries {
+				Expect(fmt.Sprint(entry.KeyVals...)).NotTo(ContainSubstring(someIban))
+			}
+			Expect(tracer.Spans()).To(HaveLen(2))
+			for _, span := range tracer.Spans() {
+				Expect(span.Err).To(HaveOccurred())
+				Expect(span.Err.Error()).NotTo(ContainSubstring(someIban))
+				Expect(fmt.Sprint(span.Attributes)).NotTo(ContainSubstring(someIban))
+			}
+		})
+
+		It("does not log messages below log level", func() {
+			logger := &recordingLogger{}
+			client := restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					Logger:           logger,
+					LogLevel:         restresourcehandler.LogLevelWarn,
+					LogBodies:        true,
+					RetryPolicy:      &restresourcehandler.RetryPolicy{MaxAttempts: 2},
+				})
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+				ghttp.RespondWith(http.StatusNoContent, nil))
+
+			err := client.Delete(context.Background(), "1", nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.messages()).To(Equal([]string{"warn: retrying http request"}))
+		})
+	})
+
+	Context("with auth provider", func() {
+		var client *restresourcehandler.RestResourceHandler
+		var authProvider *fakeAuthProvider
+
+		BeforeEach(func() {
+			authProvider = &fakeAuthProvider{}
+			client = restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					AuthProvider:     authProvider,
+				})
+		})
+
+		It("authenticates requests", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("Authorization", "Bearer token-0"),
+					ghttp.RespondWith(http.StatusNoContent, nil)))
+
+			err := client.Delete(context.Background(), "1", nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(authProvider.invalidations).To(BeZero())
+		})
+
+		It("sends request once more with fresh credentials after 401", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("Authorization", "Bearer token-0"),
+					ghttp.RespondWith(http.StatusUnauthorized, nil)),
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("Authorization", "Bearer token-1"),
+					ghttp.VerifyJSONRepresenting(person{"Smith"}),
+					ghttp.RespondWithJSONEncoded(http.StatusCreated, person{"Smith"})))
+
+			var response person
+			err := client.Create(context.Background(), person{"Smith"}, &response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(authProvider.invalidations).To(Equal(1))
+		})
+
+		It("gives up after second 401", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusUnauthorized, nil),
+				ghttp.RespondWith(http.StatusUnauthorized, nil))
+
+			err := client.Delete(context.Background(), "1", nil)
+
+			Expect(err).To(beRemoteError(http.StatusUnauthorized))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+	})
+
+	Context("with interceptors", func() {
+		newClient := func(interceptors ...restresourcehandler.Interceptor) *restresourcehandler.RestResourceHandler {
+			return restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					IsDataWrapped:    true,
+					DataPropertyName: "data",
+					Interceptors:     interceptors,
+				})
+		}
+
+		It("calls interceptors in order with operation details", func() {
+			var calls []string
+			var operations []restresourcehandler.Operation
+			recordingInterceptor := func(name string) restresourcehandler.Interceptor {
+				return func(
+					ctx context.Context,
+					op *restresourcehandler.Operation,
+					next restresourcehandler.Invoker) error {
+					calls = append(calls, "before "+name)
+					operations = append(operations, *op)
+					err := next(ctx, op)
+					calls = append(calls, "after "+name)
+
+					return err
+				}
+			}
+			client := newClient(recordingInterceptor("first"), recordingInterceptor("second"))
+			server.AppendHandlers(ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{person{"Smith"}}))
+
+			var response person
+			err := client.Fetch(context.Background(), "1", map[string]string{"attrs": "name"}, &response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal([]string{"before first", "before second", "after second", "after first"}))
+			Expect(operations[0]).To(SatisfyAll(
+				HaveField("Method", http.MethodGet),
+				HaveField("ResourceURL", url),
+				HaveField("ResourceID", "1"),
+				HaveField("QueryParams", map[string]string{"attrs": "name"}),
+				HaveField("Resource", BeNil()),
+				HaveField("Response", BeIdenticalTo(&response))))
+		})
+
+		It("lets interceptors modify the request", func() {
+			client := newClient(func(
+				ctx context.Context,
+				op *restresourcehandler.Operation,
+				next restresourcehandler.Invoker) error {
+				op.Header.Set("X-Resource-Name", op.Resource.(person).Name)
+				op.Resource = person{"Doe"}
+
+				return next(ctx, op)
+			})
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("X-Resource-Name", "Smith"),
+					ghttp.VerifyJSONRepresenting(wrapper{person{"Doe"}}),
+					ghttp.RespondWithJSONEncoded(http.StatusCreated, wrapper{person{"Doe"}})))
+
+			var response person
+			err := client.Create(context.Background(), person{"Smith"}, &response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(person{"Doe"}))
+		})
+
+		It("does not let interceptors modify query params of the caller", func() {
+			client := newClient(func(
+				ctx context.Context,
+				op *restresourcehandler.Operation,
+				next restresourcehandler.Invoker) error {
+				op.QueryParams["attrs"] = "id"
+				op.QueryParams["trace"] = "1"
+
+				return next(ctx, op)
+			})
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", resourcePath+"/1", "attrs=id&trace=1"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{person{"Smith"}})))
+			queryParams := map[string]string{"attrs": "name"}
+
+			var response person
+			err := client.Fetch(context.Background(), "1", queryParams, &response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queryParams).To(Equal(map[string]string{"attrs": "name"}))
+		})
+
+		It("does not retry requests which cannot be constructed", func() {
+			logger := &recordingLogger{}
+			client := restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					Logger:           logger,
+					RetryPolicy:      &restresourcehandler.RetryPolicy{MaxAttempts: 3},
+					Interceptors: []restresourcehandler.Interceptor{func(
+						ctx context.Context,
+						op *restresourcehandler.Operation,
+						next restresourcehandler.Invoker) error {
+						op.Resource = make(chan int)
+
+						return next(ctx, op)
+					}},
+				})
+
+			var response person
+			err := client.Fetch(context.Background(), "1", nil, &response)
+
+			Expect(err).To(HaveOccurred())
+			Expect(logger.messages()).NotTo(ContainElement("warn: retrying http request"))
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("lets interceptors short-circuit the operation", func() {
+			client := newClient(func(
+				ctx context.Context,
+				op *restresourcehandler.Operation,
+				next restresourcehandler.Invoker) error {
+				*op.Response.(*person) = person{"Cached"}
+
+				return nil
+			})
+
+			var response person
+			err := client.Fetch(context.Background(), "1", nil, &response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(person{"Cached"}))
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("lets interceptors inspect the result and the error", func() {
+			var result person
+			var resultErr error
+			client := newClient(func(
+				ctx context.Context,
+				op *restresourcehandler.Operation,
+				next restresourcehandler.Invoker) error {
+				resultErr = next(ctx, op)
+				if resultErr == nil {
+					result = *op.Response.(*person)
+				}
+
+				return resultErr
+			})
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{person{"Smith"}}),
+				ghttp.RespondWith(http.StatusNotFound, nil))
+
+			var response person
+			Expect(client.Fetch(context.Background(), "1", nil, &response)).To(Succeed())
+			Expect(result).To(Equal(person{"Smith"}))
+
+			err := client.Fetch(context.Background(), "2", nil, &response)
+			Expect(err).To(beRemoteError(http.StatusNotFound))
+			Expect(resultErr).To(MatchError(err))
+		})
+	})
+
+	Context("with codecs", func() {
+		type xmlPerson struct {
+			Name string `xml:"name"`
+		}
+
+		type xmlWrapper struct {
+			XMLName xml.Name  `xml:"envelope"`
+			Data    xmlPerson `xml:"data"`
+		}
+
+		respondWithXML := func(statusCode int, object interface{}) http.HandlerFunc {
+			payload, err := xml.Marshal(object)
+			Expect(err).NotTo(HaveOccurred())
+
+			return ghttp.RespondWith(statusCode, payload, http.Header{"Content-Type": []string{"application/xml"}})
+		}
+
+		It("sends and receives resources using configured codec", func() {
+			client := restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					Codec:            restresourcehandler.XMLCodec{},
+					IsDataWrapped:    true,
+					DataPropertyName: "data",
+				})
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyContentType("application/xml"),
+					ghttp.VerifyHeaderKV("Accept", "application/xml"),
+					ghttp.VerifyBody([]byte(`<envelope><data><name>Smith</name></data></envelope>`)),
+					respondWithXML(http.StatusCreated, xmlWrapper{Data: xmlPerson{Name: "Smith"}})))
+
+			var response xmlPerson
+			err := client.Create(context.Background(), xmlPerson{Name: "Smith"}, &response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Name).To(Equal("Smith"))
+		})
+
+		It("decodes response with codec matching its content type", func() {
+			client := restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					Codec:            restresourcehandler.JSONCodec{},
+					AcceptedCodecs:   []restresourcehandler.Codec{restresourcehandler.XMLCodec{}},
+					IsDataWrapped:    true,
+					DataPropertyName: "data",
+				})
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("Accept", "application/json, application/xml"),
+					respondWithXML(http.StatusOK, xmlWrapper{Data: xmlPerson{Name: "Smith"}})),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{person{"Doe"}}))
+
+			var xmlResponse xmlPerson
+			Expect(client.Fetch(context.Background(), "1", nil, &xmlResponse)).To(Succeed())
+			Expect(xmlResponse.Name).To(Equal("Smith"))
+
+			var jsonResponse person
+			Expect(client.Fetch(context.Background(), "2", nil, &jsonResponse)).To(Succeed())
+			Expect(jsonResponse).To(Equal(person{"Doe"}))
+		})
+
+		It("fails when response lacks data property", func() {
+			client := restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding: resourceEncoding,
+					IsDataWrapped:    true,
+					DataPropertyName: "data",
+				})
+			server.AppendHandlers(ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]string{"other": "value"}))
+
+			var response person
+			err := client.Fetch(context.Background(), "1", nil, &response)
+
+			Expect(err).To(MatchError(restresourcehandler.ErrInvalidResponse))
+		})
+	})
+
+	Context("with metadata", func() {
+		var client *restresourcehandler.RestResourceHandler
+
+		BeforeEach(func() {
+			client = restresourcehandler.MustNew(
+				httpClient,
+				url,
+				restresourcehandler.Config{
+					ResourceEncoding:  resourceEncoding,
+					IsDataWrapped:     true,
+					DataPropertyName:  "data",
+					LinksPropertyName: "links",
+					MetaPropertyName:  "meta",
+					RetryPolicy:       &restresourcehandler.RetryPolicy{MaxAttempts: 2},
+				})
+		})
+
+		It("returns response metadata", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+				ghttp.RespondWith(
+					http.StatusOK,
+					`{"data":[{"name":"Smith"}],"links":{"self":"/api/people"},"meta":{"total":1}}`,
+					http.Header{
+						"Content-Type":          []string{"application/json"},
+						"X-Request-Id":          []string{"some-request-id"},
+						"X-Ratelimit-Limit":     []string{"1000"},
+						"X-Ratelimit-Remaining": []string{"999"},
+						"X-Ratelimit-Reset":     []string{"1700000000"},
+					}))
+
+			var response []person
+			metadata, err := client.ListWithMetadata(context.Background(), nil, &response)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal([]person{{"Smith"}}))
+			Expect(metadata.StatusCode).To(Equal(http.StatusOK))
+			Expect(metadata.Header.Get("Content-Type")).To(Equal("application/json"))
+			Expect(metadata.RequestID).To(Equal("some-request-id"))
+			Expect(metadata.RateLimit).To(Equal(&restresourcehandler.RateLimit{
+				Limit:     1000,
+				Remaining: 999,
+				Reset:     time.Unix(1700000000, 0),
+			}))
+			Expect(metadata.Links).To(Equal(restresourcehandler.Links{Self: "/api/people"}))
+			Expect(string(metadata.Meta)).To(Equal(`{"total":1}`))
+			Expect(metadata.Attempts).To(Equal(2))
+			Expect(metadata.Latency).To(BeNumerically(">", 0))
+		})
+
+		It("returns response metadata along with remote error", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(
+					http.StatusNotFound,
+					nil,
+					http.Header{"X-Request-Id": []string{"some-request-id"}}))
+
+			var response person
+			metadata, err := client.FetchWithMetadata(context.Background(), "1", nil, &response)
+
+			Expect(err).To(beRemoteError(http.StatusNotFound))
+			Expect(metadata.StatusCode).To(Equal(http.StatusNotFound))
+			Expect(metadata.RequestID).To(Equal("some-request-id"))
+			Expect(metadata.RateLimit).To(BeNil())
+			Expect(metadata.Attempts).To(Equal(1))
+		})
+
+		It("returns response metadata along with transport error", func() {
+			closeConnection := func(w http.ResponseWriter, req *http.Request) {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				Expect(err).NotTo(HaveOccurred())
+				conn.Close()
+			}
+			server.AppendHandlers(closeConnection, closeConnection)
+
+			var response person
+			metadata, err := client.FetchWithMetadata(context.Background(), "1", nil, &response)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err).NotTo(MatchError(restresourcehandler.ErrRemoteError))
+			Expect(metadata.StatusCode).To(BeZero())
+			Expect(metadata.Attempts).To(Equal(2))
+			Expect(metadata.Latency).To(BeNumerically(">", 0))
+		})
+
+		It("returns response metadata of delete", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(
+					http.StatusNoContent,
+					nil,
+					http.Header{"X-Request-Id": []string{"some-request-id"}}))
+
+			metadata, err := client.DeleteWithMetadata(context.Background(), "1", nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata.StatusCode).To(Equal(http.StatusNoContent))
+			Expect(metadata.RequestID).To(Equal("some-request-id"))
+		})
+	})
 })
@@ -0,0 +1,164 @@
+package restresourcehandler
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// jitter is the random source of the retry delays. It is seeded per process (the global math/rand source
+// is not seeded before Go 1.20), so that clients started at the same time do not retry in lockstep.
+var jitter = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))} //nolint:gosec // jitter does not need a secure random source
+
+// RetryPolicy represents a policy of retrying failed requests.
+//
+// Requests are retried on transport errors (apart from requests rejected by an open circuit breaker,
+// see CircuitBreaker) and on responses with HTTP status 429 (Too Many Requests)
+// or 5xx. Only idempotent requests are retried, i.e. GET and DELETE requests
+// and POST requests sent with an idempotency key.
+//
+// Delays between attempts use exponential backoff with full jitter: the n-th retry is delayed
+// by a random duration between 0 and min(MaxDelay, BaseDelay * 2^(n-1)).
+// If the server sends a Retry-After header, the delay requested by the server is used instead
+// (capped at MaxDelay).
+type RetryPolicy struct {
+	// MaxAttempts is the maximal number of attempts to send a request (including the first one).
+	MaxAttempts int
+	// BaseDelay is the upper bound of the delay before the first retry.
+	BaseDelay time.Duration
+	// MaxDelay is the maximal delay between two attempts.
+	MaxDelay time.Duration
+}
+
+// validateRetryPolicy does a sanity check of a RetryPolicy instance.
+func validateRetryPolicy(policy RetryPolicy) error {
+	if policy.MaxAttempts < 1 {
+		return &ConfigError{"RetryPolicy.MaxAttempts", "RetryPolicy.MaxAttempts must be positive."}
+	}
+
+	if policy.BaseDelay < 0 {
+		return &ConfigError{"RetryPolicy.BaseDelay", "RetryPolicy.BaseDelay must not be negative."}
+	}
+
+	if policy.MaxDelay < policy.BaseDelay {
+		return &ConfigError{"RetryPolicy.MaxDelay", "RetryPolicy.MaxDelay must not be less than RetryPolicy.BaseDelay."}
+	}
+
+	return nil
+}
+
+// retryDelay decides if a request should be retried after the given attempt (numbered from 1)
+// ended with the given response or error. Returns the delay to wait before the next attempt.
+// A nil policy never retries.
+func (p *RetryPolicy) retryDelay(
+	ctx context.Context,
+	params requestParams,
+	attempt int,
+	resp *http.Response,
+	err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts || ctx.Err() != nil || !isIdempotent(params) {
+		return 0, false
+	}
+
+	if errors.Is(err, ErrCircuitOpen) {
+		return 0, false
+	}
+
+	if err == nil && !isRetryableStatus(resp.StatusCode) {
+		return 0, false
+	}
+
+	if resp != nil {
+		if delay, ok := retryAfter(resp.Header); ok {
+			return p.capDelay(delay), true
+		}
+	}
+
+	return p.backoff(attempt), true
+}
+
+// backoff computes a random delay before the retry following the given attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	limit := p.BaseDelay
+	for i := 1; i < attempt && limit < p.MaxDelay; i++ {
+		limit *= 2
+	}
+
+	if limit > p.MaxDelay {
+		limit = p.MaxDelay
+	}
+
+	if limit <= 0 {
+		return 0
+	}
+
+	jitter.Lock()
+	defer jitter.Unlock()
+
+	return time.Duration(jitter.Int63n(int64(limit) + 1))
+}
+
+func (p *RetryPolicy) capDelay(delay time.Duration) time.Duration {
+	if delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+
+	if delay < 0 {
+		return 0
+	}
+
+	return delay
+}
+
+func isIdempotent(params requestParams) bool {
+	switch params.HTTPMethod {
+	case http.MethodGet, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return params.IdempotencyKey != ""
+	default:
+		return false
+	}
+}
+
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// retryAfter parses the Retry-After header (either delay in seconds or an HTTP date).
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+
+	return 0, false
+}
+
+// sleep waits for the given duration or until the context is done.
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // the caller wraps this error
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,157 @@
+package restresourcehandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func someValidRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		MaxDelay:    10 * time.Second,
+	}
+}
+
+func responseWithStatus(statusCode int, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{StatusCode: statusCode, Header: header}
+}
+
+var _ = Describe("RetryPolicy", func() {
+	Context("fails validation", func() {
+		It("when max attempts is not positive", func() {
+			policy := someValidRetryPolicy()
+			policy.MaxAttempts = 0
+
+			Expect(validateRetryPolicy(policy)).
+				To(MatchError(&ConfigError{"RetryPolicy.MaxAttempts", "RetryPolicy.MaxAttempts must be positive."}))
+		})
+
+		It("when base delay is negative", func() {
+			policy := someValidRetryPolicy()
+			policy.BaseDelay = -time.Second
+
+			Expect(validateRetryPolicy(policy)).
+				To(MatchError(&ConfigError{"RetryPolicy.BaseDelay", "RetryPolicy.BaseDelay must not be negative."}))
+		})
+
+		It("when max delay is less than base delay", func() {
+			policy := someValidRetryPolicy()
+			policy.MaxDelay = policy.BaseDelay / 2
+
+			Expect(validateRetryPolicy(policy)).
+				To(MatchError(&ConfigError{
+					"RetryPolicy.MaxDelay",
+					"RetryPolicy.MaxDelay must not be less than RetryPolicy.BaseDelay.",
+				}))
+		})
+	})
+
+	DescribeTable("computes backoff with full jitter",
+		func(attempt int, expectedLimit time.Duration) {
+			policy := someValidRetryPolicy()
+
+			for i := 0; i < 100; i++ {
+				Expect(policy.backoff(attempt)).To(BeNumerically("<=", expectedLimit))
+				Expect(policy.backoff(attempt)).To(BeNumerically(">=", 0))
+			}
+		},
+		Entry("after first attempt", 1, time.Second),
+		Entry("after second attempt", 2, 2*time.Second),
+		Entry("after third attempt", 3, 4*time.Second),
+		Entry("capped at max delay", 10, 10*time.Second),
+		Entry("capped at max delay without overflow", 100, 10*time.Second),
+	)
+
+	DescribeTable("decides whether to retry",
+		func(method string, idempotencyKey string, attempt int, resp *http.Response, err error, expected bool) {
+			policy := someValidRetryPolicy()
+			params := requestParams{HTTPMethod: method, IdempotencyKey: idempotencyKey}
+
+			_, doRetry := policy.retryDelay(context.Background(), params, attempt, resp, err)
+
+			Expect(doRetry).To(Equal(expected))
+		},
+		Entry("GET on 503", http.MethodGet, "", 1, responseWithStatus(http.StatusServiceUnavailable, nil), nil, true),
+		Entry("GET on 500", http.MethodGet, "", 1, responseWithStatus(http.StatusInternalServerError, nil), nil, true),
+		Entry("GET on 429", http.MethodGet, "", 1, responseWithStatus(http.StatusTooManyRequests, nil), nil, true),
+		Entry("GET on transport error", http.MethodGet, "", 1, nil, errors.New("some error"), true), //nolint:goerr113,lll // not a problem here
+		Entry("DELETE on 503", http.MethodDelete, "", 1, responseWithStatus(http.StatusServiceUnavailable, nil), nil, true),
+		Entry("POST with idempotency key on 503",
+			http.MethodPost, "some-key", 1, responseWithStatus(http.StatusServiceUnavailable, nil), nil, true),
+		Entry("not POST without idempotency key on 503",
+			http.MethodPost, "", 1, responseWithStatus(http.StatusServiceUnavailable, nil), nil, false),
+		Entry("not PATCH on 503", http.MethodPatch, "", 1, responseWithStatus(http.StatusServiceUnavailable, nil), nil, false),
+		Entry("not GET on 404", http.MethodGet, "", 1, responseWithStatus(http.StatusNotFound, nil), nil, false),
+		Entry("not GET on 200", http.MethodGet, "", 1, responseWithStatus(http.StatusOK, nil), nil, false),
+		Entry("not GET after last attempt",
+			http.MethodGet, "", 3, responseWithStatus(http.StatusServiceUnavailable, nil), nil, false),
+	)
+
+	It("does not retry when context is done", func() {
+		policy := someValidRetryPolicy()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, doRetry := policy.retryDelay(
+			ctx, requestParams{HTTPMethod: http.MethodGet}, 1, nil, errors.New("some error")) //nolint:goerr113,lll // not a problem here
+
+		Expect(doRetry).To(BeFalse())
+	})
+
+	It("never retries when there is no policy", func() {
+		var policy *RetryPolicy
+
+		_, doRetry := policy.retryDelay(
+			context.Background(),
+			requestParams{HTTPMethod: http.MethodGet},
+			1,
+			responseWithStatus(http.StatusServiceUnavailable, nil),
+			nil)
+
+		Expect(doRetry).To(BeFalse())
+	})
+
+	DescribeTable("honours Retry-After header",
+		func(retryAfter string, expectedDelay time.Duration) {
+			policy := someValidRetryPolicy()
+
+			delay, doRetry := policy.retryDelay(
+				context.Background(),
+				requestParams{HTTPMethod: http.MethodGet},
+				1,
+				responseWithStatus(http.StatusTooManyRequests, http.Header{"Retry-After": []string{retryAfter}}),
+				nil)
+
+			Expect(doRetry).To(BeTrue())
+			Expect(delay).To(BeNumerically("~", expectedDelay, time.Second))
+		},
+		Entry("in seconds", "3", 3*time.Second),
+		Entry("in seconds capped at max delay", "120", 10*time.Second),
+		Entry("as HTTP date in the past", "Mon, 02 Jan 2006 15:04:05 GMT", time.Duration(0)),
+	)
+
+	It("honours Retry-After header as HTTP date", func() {
+		policy := someValidRetryPolicy()
+		retryAfter := time.Now().Add(5 * time.Second).UTC().Format(http.TimeFormat)
+
+		delay, doRetry := policy.retryDelay(
+			context.Background(),
+			requestParams{HTTPMethod: http.MethodGet},
+			1,
+			responseWithStatus(http.StatusServiceUnavailable, http.Header{"Retry-After": []string{retryAfter}}),
+			nil)
+
+		Expect(doRetry).To(BeTrue())
+		Expect(delay).To(BeNumerically("~", 5*time.Second, time.Second))
+	})
+})
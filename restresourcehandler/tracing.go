@@ -0,0 +1,136 @@
+package restresourcehandler
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Tracer starts the spans of the operations performed by RestResourceHandlers (see Config.Tracer).
+// Every operation gets a span (e.g. "accounts GET") with a child span for every HTTP request attempt
+// (e.g. "HTTP GET"). The trace context of the attempt span is sent in the W3C "traceparent"
+// and "tracestate" headers. See RecordingTracer for a Tracer capturing the spans in tests.
+type Tracer interface {
+	// Start starts a span with the given name as a child of the span carried by ctx (if any).
+	// Returns a context carrying the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced unit of work (see Tracer).
+type Span interface {
+	// SetAttribute sets an attribute of the span (see the Attribute... constants).
+	SetAttribute(key string, value interface{})
+	// RecordError records the error the unit of work failed with.
+	RecordError(err error)
+	// TraceContext returns the trace context propagated to the server (see TraceContext.IsValid).
+	TraceContext() TraceContext
+	// End ends the span.
+	End()
+}
+
+// Span attributes set by RestResourceHandler.
+const (
+	// AttributeResource is the name of the handled resource (see Config.ResourceName).
+	AttributeResource = "resource"
+	// AttributeResourceID is the id of the targeted resource (set if the operation targets a single resource).
+	AttributeResourceID = "resource.id"
+	// AttributeHTTPMethod is the HTTP method of the operation.
+	AttributeHTTPMethod = "http.method"
+	// AttributeHTTPURL is the URL of a request attempt.
+	AttributeHTTPURL = "http.url"
+	// AttributeHTTPStatusCode is the HTTP status of the (final) response.
+	AttributeHTTPStatusCode = "http.status_code"
+	// AttributeRetryAttempt is the number of a request attempt (starting with 1)
+	// or the number of all attempts made in case of an operation span.
+	AttributeRetryAttempt = "retry.attempt"
+)
+
+// TraceContext is a W3C trace context (https://www.w3.org/TR/trace-context/).
+type TraceContext struct {
+	// TraceID identifies the whole trace.
+	TraceID [16]byte
+	// SpanID identifies the span within the trace.
+	SpanID [8]byte
+	// Sampled denotes if the trace is recorded.
+	Sampled bool
+	// State is the vendor-specific trace state sent in the "tracestate" header (empty - not sent).
+	State string
+}
+
+// IsValid reports whether the trace context has non-zero trace and span ids. Invalid contexts are not propagated.
+func (tc TraceContext) IsValid() bool {
+	return tc.TraceID != [16]byte{} && tc.SpanID != [8]byte{}
+}
+
+// Traceparent returns the value of the "traceparent" header.
+func (tc TraceContext) Traceparent() string {
+	flags := "00"
+	if tc.Sampled {
+		flags = "01"
+	}
+
+	return fmt.Sprintf("00-%s-%s-%s", hex.EncodeToString(tc.TraceID[:]), hex.EncodeToString(tc.SpanID[:]), flags)
+}
+
+// ParseTraceparent parses the value of a "traceparent" header (version 00).
+// Returns an error wrapping ErrInvalidTraceparent if the value is malformed.
+func ParseTraceparent(value string) (TraceContext, error) {
+	var tc TraceContext
+
+	parts := strings.Split(value, "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return tc, InvalidTraceparentError(fmt.Sprintf(`"%s" is not a version 00 traceparent`, value))
+	}
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return tc, InvalidTraceparentError(fmt.Sprintf(`"%s" has invalid flags`, value))
+	}
+
+	if _, err := hex.Decode(tc.TraceID[:], []byte(parts[1])); err != nil {
+		return tc, InvalidTraceparentError(fmt.Sprintf(`"%s" has invalid trace id`, value))
+	}
+
+	if _, err := hex.Decode(tc.SpanID[:], []byte(parts[2])); err != nil {
+		return tc, InvalidTraceparentError(fmt.Sprintf(`"%s" has invalid span id`, value))
+	}
+
+	if !tc.IsValid() {
+		return tc, InvalidTraceparentError(fmt.Sprintf(`"%s" has zero trace id or span id`, value))
+	}
+
+	tc.Sampled = flags[0]&1 == 1
+
+	return tc, nil
+}
+
+// injectTraceContext sets the "traceparent" and "tracestate" headers of the given request
+// if the trace context is valid.
+func injectTraceContext(header http.Header, tc TraceContext) {
+	if !tc.IsValid() {
+		return
+	}
+
+	header.Set("traceparent", tc.Traceparent())
+
+	if tc.State != "" {
+		header.Set("tracestate", tc.State)
+	}
+}
+
+// noopTracer is a Tracer which does not trace anything.
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// noopSpan is a Span which does not record anything.
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, interface{}) {}
+func (noopSpan) RecordError(error)                {}
+func (noopSpan) TraceContext() TraceContext       { return TraceContext{} }
+func (noopSpan) End()                             {}
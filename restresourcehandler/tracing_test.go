@@ -0,0 +1,136 @@
+package restresourcehandler_test
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Tracing", func() {
+	It("formats and parses traceparent", func() {
+		tc := restresourcehandler.TraceContext{
+			TraceID: [16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+			SpanID:  [8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+			Sampled: true,
+		}
+
+		Expect(tc.Traceparent()).To(Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+		Expect(restresourcehandler.ParseTraceparent(tc.Traceparent())).To(Equal(tc))
+	})
+
+	DescribeTable("fails to parse malformed traceparent",
+		func(value string) {
+			_, err := restresourcehandler.ParseTraceparent(value)
+
+			Expect(err).To(MatchError(restresourcehandler.ErrInvalidTraceparent))
+		},
+		Entry("empty", ""),
+		Entry("unknown version", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"),
+		Entry("short trace id", "00-4bf92f3577b34da6-00f067aa0ba902b7-01"),
+		Entry("non-hex span id", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902bx-01"),
+		Entry("zero trace id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01"),
+	)
+
+	Context("with handler", func() {
+		var server *ghttp.Server
+		var tracer *restresourcehandler.RecordingTracer
+		var client *restresourcehandler.RestResourceHandler
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+			tracer = restresourcehandler.NewRecordingTracer()
+			tracer.State = "vendor=value"
+			client = restresourcehandler.MustNew(
+				&http.Client{},
+				server.URL()+"/api/people",
+				restresourcehandler.Config{
+					ResourceEncoding: "application/json",
+					ResourceName:     "people",
+					RetryPolicy:      &restresourcehandler.RetryPolicy{MaxAttempts: 2},
+					Tracer:           tracer,
+				})
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("records operation and attempt spans propagating trace context", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, person{"Smith"}))
+
+			var response person
+			Expect(client.Fetch(context.Background(), "1", nil, &response)).To(Succeed())
+
+			spans := tracer.Spans()
+			Expect(spans).To(HaveLen(3))
+
+			firstAttempt, secondAttempt, operation := spans[0], spans[1], spans[2]
+
+			Expect(operation.Name).To(Equal("people GET"))
+			Expect(operation.ParentSpanID).To(BeZero())
+			Expect(operation.Err).NotTo(HaveOccurred())
+			Expect(operation.Attributes).To(Equal(map[string]interface{}{
+				restresourcehandler.AttributeResource:       "people",
+				restresourcehandler.AttributeResourceID:     "1",
+				restresourcehandler.AttributeHTTPMethod:     "GET",
+				restresourcehandler.AttributeHTTPStatusCode: http.StatusOK,
+				restresourcehandler.AttributeRetryAttempt:   2,
+			}))
+
+			for i, attempt := range []restresourcehandler.RecordedSpan{firstAttempt, secondAttempt} {
+				Expect(attempt.Name).To(Equal("HTTP GET"))
+				Expect(attempt.ParentSpanID).To(Equal(operation.TraceContext.SpanID))
+				Expect(attempt.TraceContext.TraceID).To(Equal(operation.TraceContext.TraceID))
+				Expect(attempt.Attributes).To(HaveKeyWithValue(restresourcehandler.AttributeRetryAttempt, i+1))
+				Expect(attempt.Attributes).To(HaveKeyWithValue(restresourcehandler.AttributeHTTPURL, server.URL()+"/api/people/1"))
+
+				request := server.ReceivedRequests()[i]
+				Expect(request.Header.Get("traceparent")).To(Equal(attempt.TraceContext.Traceparent()))
+				Expect(request.Header.Get("tracestate")).To(Equal("vendor=value"))
+			}
+
+			Expect(firstAttempt.Attributes).To(HaveKeyWithValue(restresourcehandler.AttributeHTTPStatusCode, 503))
+		})
+
+		It("records operation error", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, nil))
+
+			err := client.Delete(context.Background(), "1", nil)
+
+			Expect(err).To(HaveOccurred())
+			Expect(tracer.Spans()).To(HaveLen(2))
+			Expect(tracer.Spans()[1].Err).To(MatchError(err))
+		})
+
+		It("nests operation spans in the span of the caller", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusNoContent, nil))
+
+			ctx, parent := tracer.Start(context.Background(), "caller")
+			Expect(client.Delete(ctx, "1", nil)).To(Succeed())
+			parent.End()
+
+			spans := tracer.Spans()
+			Expect(spans).To(HaveLen(3))
+			Expect(spans[1].ParentSpanID).To(Equal(spans[2].TraceContext.SpanID))
+		})
+
+		It("does not propagate trace context without tracer", func() {
+			client = restresourcehandler.MustNew(
+				&http.Client{},
+				server.URL()+"/api/people",
+				restresourcehandler.Config{ResourceEncoding: "application/json"})
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeader(http.Header{"Traceparent": nil}),
+					ghttp.RespondWith(http.StatusNoContent, nil)))
+
+			Expect(client.Delete(context.Background(), "1", nil)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,114 @@
+package restresourcehandler
+
+import "context"
+
+// TypedResource is a type-safe view of a RestResourceHandler handling resources of type T.
+// Use NewTypedResource to construct instances of TypedResource.
+type TypedResource[T any] struct {
+	handler *RestResourceHandler
+}
+
+// NewTypedResource creates a TypedResource for resources of type T handled by the given handler.
+func NewTypedResource[T any](handler *RestResourceHandler) *TypedResource[T] {
+	return &TypedResource[T]{handler}
+}
+
+// Get fetches a resource with a given id.
+// Context can be used to control asynchronous requests.
+func (r *TypedResource[T]) Get(ctx context.Context, id string) (T, error) {
+	response, err := r.GetResponse(ctx, id)
+
+	return response.Data, err
+}
+
+// GetResponse is like Get, but returns the resource along with the response metadata.
+func (r *TypedResource[T]) GetResponse(ctx context.Context, id string) (Response[T], error) {
+	var response Response[T]
+
+	var err error
+	response.ResponseMetadata, err = r.handler.FetchWithMetadata(ctx, id, nil, &response.Data)
+
+	return response, err
+}
+
+// Create creates the given resource and returns the created resource as sent back by the server.
+// Context can be used to control asynchronous requests.
+func (r *TypedResource[T]) Create(ctx context.Context, resource T) (T, error) {
+	response, err := r.CreateResponse(ctx, resource)
+
+	return response.Data, err
+}
+
+// CreateResponse is like Create, but returns the created resource along with the response metadata.
+func (r *TypedResource[T]) CreateResponse(ctx context.Context, resource T) (Response[T], error) {
+	var response Response[T]
+
+	var err error
+	response.ResponseMetadata, err = r.handler.CreateWithMetadata(ctx, &resource, &response.Data)
+
+	return response, err
+}
+
+// Delete deletes a resource with a given id.
+// Additional query parameters (e.g. the resource version) can be specified to be sent with the request.
+// Context can be used to control asynchronous requests.
+func (r *TypedResource[T]) Delete(ctx context.Context, id string, queryParams map[string]string) error {
+	return r.handler.Delete(ctx, id, queryParams)
+}
+
+// DeleteResponse is like Delete, but also returns the response metadata.
+func (r *TypedResource[T]) DeleteResponse(
+	ctx context.Context,
+	id string,
+	queryParams map[string]string) (ResponseMetadata, error) {
+	return r.handler.DeleteWithMetadata(ctx, id, queryParams)
+}
+
+// Patch updates a resource with a given id using the given patch
+// (i.e. an object containing only the changed properties) and returns the updated resource.
+// Context can be used to control asynchronous requests.
+func (r *TypedResource[T]) Patch(ctx context.Context, id string, patch interface{}) (T, error) {
+	response, err := r.PatchResponse(ctx, id, patch)
+
+	return response.Data, err
+}
+
+// PatchResponse is like Patch, but returns the updated resource along with the response metadata.
+func (r *TypedResource[T]) PatchResponse(ctx context.Context, id string, patch interface{}) (Response[T], error) {
+	var response Response[T]
+
+	var err error
+	response.ResponseMetadata, err = r.handler.PatchWithMetadata(ctx, id, patch, &response.Data)
+
+	return response, err
+}
+
+// List lists resources page by page, starting with the page described by the given query parameters
+// (e.g. paging or filtering parameters).
+// Returns an iterator which fetches the pages lazily, following the "next" links sent by the server
+// (see Config.LinksPropertyName).
+// Context can be used to control asynchronous requests of all page fetches.
+func (r *TypedResource[T]) List(ctx context.Context, queryParams map[string]string) *Iterator[T] {
+	if queryParams == nil {
+		queryParams = map[string]string{}
+	}
+
+	return &Iterator[T]{
+		ctx:                 ctx,
+		handler:             r.handler,
+		queryParams:         queryParams,
+		nextPageQueryParams: queryParams,
+	}
+}
+
+// ListPage fetches a single page of resources described by the given query parameters
+// along with the response metadata (e.g. the links to the other pages).
+// Context can be used to control asynchronous requests.
+func (r *TypedResource[T]) ListPage(ctx context.Context, queryParams map[string]string) (Response[[]T], error) {
+	var response Response[[]T]
+
+	var err error
+	response.ResponseMetadata, err = r.handler.ListWithMetadata(ctx, queryParams, &response.Data)
+
+	return response, err
+}
@@ -0,0 +1,257 @@
+package restresourcehandler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/jannis-baratheon/form3-take-home-exercise/restresourcehandler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("TypedResource", func() {
+	var server *ghttp.Server
+	var people *restresourcehandler.TypedResource[person]
+
+	const resourcePath = "/api/people"
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		people = restresourcehandler.NewTypedResource[person](restresourcehandler.MustNew(
+			&http.Client{},
+			server.URL()+resourcePath,
+			restresourcehandler.Config{
+				ResourceEncoding:  "application/json",
+				IsDataWrapped:     true,
+				DataPropertyName:  "data",
+				LinksPropertyName: "links",
+			}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("gets resource", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", resourcePath+"/1"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{person{"Smith"}})))
+
+		response, err := people.Get(context.Background(), "1")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response).To(Equal(person{"Smith"}))
+	})
+
+	It("creates resource", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", resourcePath),
+				ghttp.VerifyJSONRepresenting(wrapper{person{"Smith"}}),
+				ghttp.RespondWithJSONEncoded(http.StatusCreated, wrapper{person{"Doe"}})))
+
+		response, err := people.Create(context.Background(), person{"Smith"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response).To(Equal(person{"Doe"}))
+	})
+
+	It("deletes resource", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", resourcePath+"/1", "version=2"),
+				ghttp.RespondWith(http.StatusNoContent, nil)))
+
+		err := people.Delete(context.Background(), "1", map[string]string{"version": "2"})
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("patches resource", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PATCH", resourcePath+"/1"),
+				ghttp.VerifyJSON(`{"data": {"name": "Doe"}}`),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, wrapper{person{"Doe"}})))
+
+		response, err := people.Patch(context.Background(), "1", map[string]string{"name": "Doe"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response).To(Equal(person{"Doe"}))
+	})
+
+	It("gets resource along with response metadata", func() {
+		server.AppendHandlers(
+			ghttp.RespondWithJSONEncoded(
+				http.StatusOK,
+				wrapper{person{"Smith"}},
+				http.Header{"X-Request-Id": []string{"some-request-id"}}))
+
+		response, err := people.GetResponse(context.Background(), "1")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.Data).To(Equal(person{"Smith"}))
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(response.RequestID).To(Equal("some-request-id"))
+	})
+
+	It("returns zero value and error on failure", func() {
+		server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, nil))
+
+		response, err := people.Get(context.Background(), "1")
+
+		Expect(err).To(beRemoteError(http.StatusNotFound))
+		Expect(response).To(BeZero())
+	})
+
+	Context("on listing", func() {
+		It("iterates over all pages following next links", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", resourcePath, "filter[name]=S&page[size]=2"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, collectionWrapper{
+						Data:  []person{{"Smith"}, {"Stone"}},
+						Links: restresourcehandler.Links{Next: resourcePath + "?page%5Bnumber%5D=1&page%5Bsize%5D=2"},
+					})),
+				ghttp.CombineHandlers(
+					// the filter is not repeated in the link, so it is taken from the first page
+					ghttp.VerifyRequest("GET", resourcePath, "filter[name]=S&page[number]=1&page[size]=2"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, collectionWrapper{
+						Data: []person{{"Swift"}},
+					})))
+
+			it := people.List(context.Background(), map[string]string{"filter[name]": "S", "page[size]": "2"})
+
+			var names []string
+			for it.Next() {
+				names = append(names, it.Value().Name)
+			}
+
+			Expect(it.Err()).NotTo(HaveOccurred())
+			Expect(names).To(Equal([]string{"Smith", "Stone", "Swift"}))
+		})
+
+		It("fails for next link with repeated query params", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, collectionWrapper{
+					Data:  []person{{"Smith"}},
+					Links: restresourcehandler.Links{Next: resourcePath + "?filter%5Bname%5D=S&filter%5Bname%5D=T"},
+				}))
+
+			it := people.List(context.Background(), nil)
+
+			Expect(it.Next()).To(BeTrue())
+			Expect(it.Value()).To(Equal(person{"Smith"}))
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.Err()).To(MatchError(restresourcehandler.ErrInvalidResponse))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("yields resources of the page with malformed next link before failing", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, collectionWrapper{
+					Data:  []person{{"Smith"}, {"Stone"}},
+					Links: restresourcehandler.Links{Next: "%zz"},
+				}))
+
+			it := people.List(context.Background(), nil)
+
+			var names []string
+			for it.Next() {
+				names = append(names, it.Value().Name)
+			}
+
+			Expect(names).To(Equal([]string{"Smith", "Stone"}))
+			Expect(it.Err()).To(MatchError(ContainSubstring("parsing next page link")))
+			Expect(it.Next()).To(BeFalse())
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("provides metadata of the fetched pages", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(
+					http.StatusOK,
+					collectionWrapper{
+						Data:  []person{{"Smith"}},
+						Links: restresourcehandler.Links{Self: resourcePath, Next: resourcePath + "?page%5Bnumber%5D=1"},
+					},
+					http.Header{"X-Request-Id": []string{"first-page"}}),
+				ghttp.RespondWith(
+					http.StatusInternalServerError,
+					nil,
+					http.Header{"X-Request-Id": []string{"second-page"}}))
+
+			it := people.List(context.Background(), nil)
+
+			Expect(it.Next()).To(BeTrue())
+			Expect(it.PageMetadata().RequestID).To(Equal("first-page"))
+			Expect(it.PageMetadata().Links.Self).To(Equal(resourcePath))
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.PageMetadata().RequestID).To(Equal("second-page"))
+		})
+
+		It("fetches single page", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", resourcePath, "page[number]=1"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, collectionWrapper{
+						Data:  []person{{"Smith"}, {"Stone"}},
+						Links: restresourcehandler.Links{Prev: resourcePath + "?page%5Bnumber%5D=0"},
+					})))
+
+			page, err := people.ListPage(context.Background(), map[string]string{"page[number]": "1"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Data).To(Equal([]person{{"Smith"}, {"Stone"}}))
+			Expect(page.Links.Prev).To(Equal(resourcePath + "?page%5Bnumber%5D=0"))
+		})
+
+		It("lists without query parameters", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", resourcePath, ""),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, collectionWrapper{Data: []person{{"Smith"}}})))
+
+			it := people.List(context.Background(), nil)
+
+			Expect(it.Next()).To(BeTrue())
+			Expect(it.Value()).To(Equal(person{"Smith"}))
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.Err()).NotTo(HaveOccurred())
+		})
+
+		It("stops on error", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, nil))
+
+			it := people.List(context.Background(), nil)
+
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.Err()).To(beRemoteError(http.StatusInternalServerError))
+			Expect(it.Next()).To(BeFalse())
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("stops when context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			it := people.List(ctx, nil)
+
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.Err()).To(MatchError(context.Canceled))
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("reports error of failed iterator", func() {
+			someError := errors.New("some error")
+
+			it := restresourcehandler.NewFailedIterator[person](someError)
+
+			Expect(it.Next()).To(BeFalse())
+			Expect(it.Err()).To(MatchError(someError))
+		})
+	})
+})